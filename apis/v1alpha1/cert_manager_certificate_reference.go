@@ -0,0 +1,24 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// CertManagerCertificateReference refers to a cert-manager.io/v1 Certificate
+// whose output Secret is imported into ACM.
+type CertManagerCertificateReference struct {
+	// Name of the cert-manager Certificate.
+	Name string `json:"name"`
+	// Namespace of the cert-manager Certificate. Defaults to the namespace of
+	// the Certificate.
+	Namespace string `json:"namespace,omitempty"`
+}
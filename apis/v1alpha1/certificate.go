@@ -23,6 +23,11 @@ import (
 // CertificateSpec defines the desired state of Certificate.
 type CertificateSpec struct {
 
+	// A cert-manager Certificate whose output Secret is imported into ACM instead of
+	// Certificate, PrivateKey and CertificateChain. Whenever cert-manager renews the
+	// certificate, it is re-imported under the same ARN.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	CertManagerCertificateRef *CertManagerCertificateReference `json:"certManagerCertificateRef,omitempty"`
 	// The Certificate to import into AWS Certificate Manager (ACM) to use with services that are integrated with ACM.
 	// This field is only valid when importing an existing certificate into ACM.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
@@ -41,11 +46,27 @@ type CertificateSpec struct {
 	// arn:aws:acm-pca:region:account:certificate-authority/12345678-1234-1234-1234-123456789012
 	//
 	// Regex Pattern: `^arn:[\w+=/,.@-]+:acm-pca:[\w+=/,.@-]*:[0-9]+:[\w+=,.@-]+(/[\w+=,.@-]+)*$`
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	CertificateAuthorityARN *string                                  `json:"certificateAuthorityARN,omitempty"`
 	CertificateAuthorityRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"certificateAuthorityRef,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	CertificateChain *ackv1alpha1.SecretKeyReference `json:"certificateChain,omitempty"`
+	// The name of a CertificateClass holding defaults for CertificateAuthorityARN or
+	// CertificateAuthorityRef, ExportKeyFormat, KeyAlgorithm, Options, ReplacementPolicy,
+	// RequeuePolicy, Tags and ValidationRetryPolicy. Fields that are not set on the
+	// Certificate are set from the class when the certificate is requested or imported, and
+	// tags are merged, the Certificate's taking precedence. Only RequeuePolicy and Tags
+	// apply to imported certificates. Later changes to the class do not affect existing
+	// Certificates.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	CertificateClassName *string `json:"certificateClassName,omitempty"`
+	// Ingresses and Services in the namespace of the Certificate that the AWS Load Balancer
+	// Controller should use the certificate for. Once the certificate is ISSUED, the
+	// controller adds its ARN to the alb.ingress.kubernetes.io/certificate-arn annotation
+	// of each Ingress, or the service.beta.kubernetes.io/aws-load-balancer-ssl-cert
+	// annotation of each Service, keeping the other ARNs listed there. The ARN is updated
+	// when the certificate is replaced, and removed when the consumer is removed from the
+	// list or the Certificate is deleted.
+	Consumers []*CertificateConsumer `json:"consumers,omitempty"`
 	// Fully qualified domain name (FQDN), such as www.example.com, that you want
 	// to secure with an ACM certificate. Use an asterisk (*) to create a wildcard
 	// certificate that protects several sites in the same domain. For example,
@@ -63,8 +84,20 @@ type CertificateSpec struct {
 	// validate domain ownership.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	DomainValidationOptions []*DomainValidationOption `json:"domainValidationOptions,omitempty"`
+	// The encoding of the private key written to the tls.key key of the ExportTo Secret.
+	// PKCS8, the default, writes a PKCS #8 "PRIVATE KEY" block for keys of any type.
+	// Traditional writes RSA keys as PKCS #1 "RSA PRIVATE KEY" blocks and EC keys as
+	// SEC 1 "EC PRIVATE KEY" blocks, for software that cannot read PKCS #8 keys.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	ExportKeyFormat *PrivateKeyFormat `json:"exportKeyFormat,omitempty"`
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	ExportTo *ackv1alpha1.SecretKeyReference `json:"exportTo,omitempty"`
+	// A kubernetes.io/tls Secret to import into ACM instead of Certificate, PrivateKey and
+	// CertificateChain. The tls.crt key holds the certificate, optionally followed by its
+	// chain, tls.key holds the private key and the optional ca.crt key is added to the chain.
+	// An Opaque Secret with the same keys is accepted too.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	ImportFrom *TLSSecretReference `json:"importFrom,omitempty"`
 	// Specifies the algorithm of the public and private key pair that your certificate
 	// uses to encrypt data. RSA is the default key algorithm for ACM certificates.
 	// Elliptic Curve Digital Signature Algorithm (ECDSA) keys are smaller, offering
@@ -91,7 +124,6 @@ type CertificateSpec struct {
 	// must match the algorithm family of the CA's secret key.
 	//
 	// Default: RSA_2048
-	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	KeyAlgorithm *string `json:"keyAlgorithm,omitempty"`
 	// Currently, you can use this parameter to specify whether to add the certificate
 	// to a certificate transparency log. Certificate transparency makes it possible
@@ -99,11 +131,36 @@ type CertificateSpec struct {
 	// Certificates that have not been logged typically produce an error message
 	// in a browser. For more information, see Opting Out of Certificate Transparency
 	// Logging (https://docs.aws.amazon.com/acm/latest/userguide/acm-bestpractices.html#best-practices-transparency).
+	//
+	// Set Export to ENABLED to request an exportable public certificate, for example to
+	// export it to another system than a Kubernetes Secret. It defaults to ENABLED for
+	// public certificates requested with ExportTo set, and is late initialized from the
+	// certificate otherwise. Private certificates can always be exported.
 	Options *CertificateOptions `json:"options,omitempty"`
 	// The private key that matches the public key in the certificate. This field is only valid when importing
 	// an existing certificate into ACM.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
 	PrivateKey *ackv1alpha1.SecretKeyReference `json:"privateKey,omitempty"`
+	// Controls what happens when DomainName, SubjectAlternativeNames, KeyAlgorithm or
+	// CertificateAuthorityARN change on a requested certificate. ACM cannot update these
+	// fields in place. With the default policy, Reject, the validating webhook of the
+	// controller rejects such a change, and without the webhook it sets a Terminal
+	// condition. With Replace, the controller requests a new certificate with the new
+	// parameters, switches the resource over to it once it is issued, and deletes the
+	// old certificate once it is no longer in use.
+	ReplacementPolicy *ReplacementPolicy `json:"replacementPolicy,omitempty"`
+	// Other regions to request or import the same certificate in, e.g. us-east-1 for
+	// CloudFront. Each replica is a separate certificate with its own ARN, tracked in
+	// Status.Replicas. Removing a region deletes its replica. When the certificate is
+	// replaced, its replicas are requested again and the old ones are retired like the
+	// replaced certificate. Replicas cannot be used with a private certificate authority,
+	// which only issues certificates in its own region.
+	ReplicaRegions []*string `json:"replicaRegions,omitempty"`
+	// How often the controller describes the certificate in ACM. Pending applies while the
+	// certificate, its renewal or its replacement is pending validation, Renewal while ACM is
+	// renewing it, and Settled once it is issued, shrinking as the certificate gets within 30
+	// days of expiring. Unset intervals default to 1m, 5m and 6h.
+	RequeuePolicy *RequeuePolicy `json:"requeuePolicy,omitempty"`
 	// Additional FQDNs to be included in the Subject Alternative Name extension
 	// of the ACM certificate. For example, add the name www.example.net to a certificate
 	// for which the DomainName field is www.example.com if users can reach your
@@ -129,6 +186,13 @@ type CertificateSpec struct {
 	SubjectAlternativeNames []*string `json:"subjectAlternativeNames,omitempty"`
 	// One or more resource tags to associate with the certificate.
 	Tags []*Tag `json:"tags,omitempty"`
+	// Requests the certificate again when it fails validation. ACM sets the status of
+	// a certificate that is not validated within 72 hours to VALIDATION_TIMED_OUT and
+	// never issues it. With this policy, the controller then requests a new certificate,
+	// switches the resource over to it and deletes the failed one, up to MaxAttempts
+	// times. Certificates with status FAILED are requested again too if their
+	// FailureReason is listed in FailureReasons.
+	ValidationRetryPolicy *ValidationRetryPolicy `json:"validationRetryPolicy,omitempty"`
 }
 
 // CertificateStatus defines the observed state of Certificate
@@ -144,6 +208,14 @@ type CertificateStatus struct {
 	// resource
 	// +kubebuilder:validation:Optional
 	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The Ingresses and Services whose certificate ARN annotation the controller added the
+	// ARN of the certificate to.
+	// +kubebuilder:validation:Optional
+	AnnotatedConsumers []*CertificateConsumerStatus `json:"annotatedConsumers,omitempty"`
+	// The CertificateApproval the controller acted on before requesting or importing the
+	// certificate, when a CertificatePolicy requires approval.
+	// +kubebuilder:validation:Optional
+	Approval *CertificateApprovalRecord `json:"approval,omitempty"`
 	// The time at which the certificate was requested.
 	// +kubebuilder:validation:Optional
 	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
@@ -152,6 +224,11 @@ type CertificateStatus struct {
 	// when the certificate type is AMAZON_ISSUED.
 	// +kubebuilder:validation:Optional
 	DomainValidations []*DomainValidation `json:"domainValidations,omitempty"`
+	// The ACM calls and Kubernetes changes the controller would have made for the
+	// Certificate in dry-run mode, in order. Private keys, passphrases and PEM encoded
+	// certificates are redacted.
+	// +kubebuilder:validation:Optional
+	DryRunPlan []*string `json:"dryRunPlan,omitempty"`
 	// Contains a list of Extended Key Usage X.509 v3 extension objects. Each object
 	// specifies a purpose for which the certificate public key can be used and
 	// consists of a name and an object identifier (OID).
@@ -163,6 +240,23 @@ type CertificateStatus struct {
 	// in the Certificate Manager User Guide.
 	// +kubebuilder:validation:Optional
 	FailureReason *string `json:"failureReason,omitempty"`
+	// The Gateway API listeners whose tls.certificateRefs refer to the Certificate. Gateways
+	// in another namespace must be permitted to by a ReferenceGrant in the namespace of the
+	// Certificate. Once the certificate is ISSUED, the controller sets the TLS option
+	// named by --gateway-certificate-arn-option, application-networking.k8s.aws/certificate-arn
+	// by default, of each listener whose reference resolves to the ARN of the certificate.
+	// Gateways are not watched: a new listener is picked up when the Certificate is next
+	// synced, which happens every minute while the reference of a listener does not resolve.
+	// +kubebuilder:validation:Optional
+	GatewayListeners []*CertificateGatewayListener `json:"gatewayListeners,omitempty"`
+	// The resourceVersion of the Secret ImportSourceSerial was read from. The Secret is only
+	// read again once its resourceVersion changes.
+	// +kubebuilder:validation:Optional
+	ImportSourceResourceVersion *string `json:"importSourceResourceVersion,omitempty"`
+	// The serial number of the certificate in the Secret referenced by ImportFrom or
+	// CertManagerCertificateRef. The certificate is re-imported when it differs from Serial.
+	// +kubebuilder:validation:Optional
+	ImportSourceSerial *string `json:"importSourceSerial,omitempty"`
 	// The date and time when the certificate was imported. This value exists only
 	// when the certificate type is IMPORTED.
 	// +kubebuilder:validation:Optional
@@ -200,6 +294,22 @@ type CertificateStatus struct {
 	// AMAZON_ISSUED.
 	// +kubebuilder:validation:Optional
 	RenewalSummary *RenewalSummary `json:"renewalSummary,omitempty"`
+	// The ARN of the certificate requested to replace the current one after a change
+	// to an immutable field. It becomes the resource's ARN once it is issued.
+	// +kubebuilder:validation:Optional
+	ReplacementCertificateARN *string `json:"replacementCertificateARN,omitempty"`
+	// Contains information about the validation of each domain name of the replacement
+	// certificate while it is pending validation.
+	// +kubebuilder:validation:Optional
+	ReplacementDomainValidations []*DomainValidation `json:"replacementDomainValidations,omitempty"`
+	// The certificates requested or imported in the regions listed in ReplicaRegions,
+	// with their ARN, status and domain validation records.
+	// +kubebuilder:validation:Optional
+	Replicas []*CertificateReplica `json:"replicas,omitempty"`
+	// The ARNs of certificates that were replaced and are deleted as soon as no Amazon
+	// Web Services resources are using them anymore.
+	// +kubebuilder:validation:Optional
+	RetiredCertificateARNs []*string `json:"retiredCertificateARNs,omitempty"`
 	// The reason the certificate was revoked. This value exists only when the certificate
 	// status is REVOKED.
 	// +kubebuilder:validation:Optional
@@ -239,6 +349,15 @@ type CertificateStatus struct {
 	// in the Certificate Manager User Guide.
 	// +kubebuilder:validation:Optional
 	Type *string `json:"type_,omitempty"`
+	// While the certificate is pending validation, the time at which ACM stops trying to
+	// validate it and sets its status to VALIDATION_TIMED_OUT, 72 hours after it was
+	// requested.
+	// +kubebuilder:validation:Optional
+	ValidationExpiresAt *metav1.Time `json:"validationExpiresAt,omitempty"`
+	// The number of times the certificate was requested again after failing validation,
+	// under ValidationRetryPolicy.
+	// +kubebuilder:validation:Optional
+	ValidationRetries *int64 `json:"validationRetries,omitempty"`
 }
 
 // Certificate is the Schema for the Certificates API
@@ -0,0 +1,76 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateApprovalSpec defines the decision on a Certificate awaiting
+// approval.
+type CertificateApprovalSpec struct {
+	// CertificateName is the name of the Certificate in the namespace of the
+	// approval that the decision is for.
+	CertificateName string `json:"certificateName"`
+	// Decision is Approved to let the controller request or import the
+	// certificate, or Denied to stop it from doing so.
+	// +kubebuilder:validation:Enum=Approved;Denied
+	Decision string `json:"decision"`
+	// Reason is an optional explanation of the decision.
+	Reason string `json:"reason,omitempty"`
+	// Approver is the name of the user who created the approval. It is set by
+	// the admission webhook of the controller, replacing any value given, and
+	// approvals without an approver are ignored.
+	Approver string `json:"approver,omitempty"`
+}
+
+// CertificateApproval is the Schema for the CertificateApprovals API. When a
+// CertificatePolicy requires approval, the controller only requests or
+// imports a certificate for a Certificate once a CertificateApproval
+// approves it. An approval only approves the request it was first acted on
+// for, so replacements and renewed imports with other domains, CA or key
+// algorithm need a new approval. Who may approve is controlled by who may
+// create CertificateApprovals.
+// +kubebuilder:object:root=true
+type CertificateApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable once set"
+	Spec CertificateApprovalSpec `json:"spec,omitempty"`
+}
+
+// CertificateApprovalList contains a list of CertificateApproval
+// +kubebuilder:object:root=true
+type CertificateApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateApproval `json:"items"`
+}
+
+// CertificateApprovalRecord records the CertificateApproval the controller
+// acted on.
+type CertificateApprovalRecord struct {
+	// Name of the CertificateApproval.
+	Name string `json:"name"`
+	// Decision of the CertificateApproval, Approved or Denied.
+	Decision string `json:"decision"`
+	// Approver is the name of the user who created the CertificateApproval.
+	Approver string `json:"approver"`
+	// Reason given for the decision.
+	Reason string `json:"reason,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateApproval{}, &CertificateApprovalList{})
+}
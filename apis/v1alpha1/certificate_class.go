@@ -0,0 +1,72 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateClassSpec holds the defaults a CertificateClass provides to
+// the Certificates that reference it. A field set on the Certificate takes
+// precedence over the class.
+type CertificateClassSpec struct {
+	// CertificateAuthorityARN is the ARN of the private certificate
+	// authority to request certificates from.
+	CertificateAuthorityARN *string `json:"certificateAuthorityARN,omitempty"`
+	// CertificateAuthorityRef references the ACK CertificateAuthority to
+	// request certificates from, instead of CertificateAuthorityARN.
+	CertificateAuthorityRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"certificateAuthorityRef,omitempty"`
+	// ExportKeyFormat is the ExportKeyFormat of the Certificates.
+	ExportKeyFormat *PrivateKeyFormat `json:"exportKeyFormat,omitempty"`
+	// KeyAlgorithm is the algorithm of the key pair of requested
+	// certificates.
+	KeyAlgorithm *string `json:"keyAlgorithm,omitempty"`
+	// Options holds the certificate transparency logging and export
+	// preferences of requested certificates.
+	Options *CertificateOptions `json:"options,omitempty"`
+	// ReplacementPolicy is the ReplacementPolicy of the Certificates.
+	ReplacementPolicy *ReplacementPolicy `json:"replacementPolicy,omitempty"`
+	// RequeuePolicy is the RequeuePolicy of the Certificates.
+	RequeuePolicy *RequeuePolicy `json:"requeuePolicy,omitempty"`
+	// Tags are added to the tags of the Certificates, which take precedence
+	// for the same key.
+	Tags []*Tag `json:"tags,omitempty"`
+	// ValidationRetryPolicy is the ValidationRetryPolicy of the
+	// Certificates.
+	ValidationRetryPolicy *ValidationRetryPolicy `json:"validationRetryPolicy,omitempty"`
+}
+
+// CertificateClass is the Schema for the CertificateClasses API. It holds
+// request defaults shared by the Certificates that name it in
+// Spec.CertificateClassName.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+type CertificateClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CertificateClassSpec `json:"spec,omitempty"`
+}
+
+// CertificateClassList contains a list of CertificateClass
+// +kubebuilder:object:root=true
+type CertificateClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateClass `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateClass{}, &CertificateClassList{})
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// CertificateConsumer refers to an Ingress or a Service in the namespace of
+// the Certificate whose AWS Load Balancer Controller annotation lists the
+// ARN of the certificate.
+type CertificateConsumer struct {
+	// Kind of the consumer, Ingress or Service.
+	// +kubebuilder:validation:Enum=Ingress;Service
+	Kind string `json:"kind"`
+	// Name of the consumer.
+	Name string `json:"name"`
+}
+
+// CertificateConsumerStatus records the certificate ARN the controller
+// added to the annotation of a consumer.
+type CertificateConsumerStatus struct {
+	// Kind of the consumer, Ingress or Service.
+	Kind string `json:"kind"`
+	// Name of the consumer.
+	Name string `json:"name"`
+	// CertificateARN is the ARN added to the annotation of the consumer.
+	CertificateARN string `json:"certificateARN"`
+}
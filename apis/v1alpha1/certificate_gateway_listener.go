@@ -0,0 +1,40 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// CertificateGatewayListener is a Gateway API listener whose
+// tls.certificateRefs refer to the Certificate, and whether the reference
+// resolves to the ARN of the certificate.
+type CertificateGatewayListener struct {
+	// Namespace of the Gateway.
+	Namespace string `json:"namespace"`
+	// Gateway is the name of the Gateway.
+	Gateway string `json:"gateway"`
+	// Listener is the name of the listener.
+	Listener string `json:"listener"`
+	// ResolvedRefs is True once the reference is permitted and the
+	// certificate is issued, and False otherwise.
+	ResolvedRefs string `json:"resolvedRefs"`
+	// Reason is RefNotPermitted when the Gateway is in another namespace
+	// and no ReferenceGrant permits the reference, Pending while the
+	// certificate is not issued, RefRemoved when the listener no longer
+	// refers to the Certificate but still has its ARN, and ResolvedRefs
+	// otherwise.
+	Reason string `json:"reason"`
+	// Message is a human readable explanation of Reason.
+	Message string `json:"message,omitempty"`
+	// CertificateARN is the ARN in the certificate ARN TLS option of the
+	// listener.
+	CertificateARN string `json:"certificateARN,omitempty"`
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificatePolicySpec defines the certificates the Certificates in a set
+// of namespaces may request or import. A list that is empty does not
+// restrict the corresponding field.
+type CertificatePolicySpec struct {
+	// Namespaces are the names of namespaces the policy applies to.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// NamespaceSelector selects namespaces the policy applies to by their
+	// labels, in addition to Namespaces. An empty selector selects all
+	// namespaces.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// DomainPatterns are the domain names certificates may be requested or
+	// imported for. A pattern starting with "*." permits every subdomain of
+	// the rest of the pattern, including wildcard names, and other patterns
+	// permit only that domain name.
+	DomainPatterns []string `json:"domainPatterns,omitempty"`
+	// CertificateAuthorityARNs are the ARNs of the private certificate
+	// authorities certificates may be requested from. When set, public
+	// certificates may not be requested. It does not apply to imported
+	// certificates.
+	CertificateAuthorityARNs []string `json:"certificateAuthorityARNs,omitempty"`
+	// KeyAlgorithms are the algorithms of the key pairs certificates may be
+	// requested or imported with, e.g. RSA_2048 or EC_prime256v1.
+	KeyAlgorithms []string `json:"keyAlgorithms,omitempty"`
+	// RequireApproval makes the certificates the policy permits wait for a
+	// CertificateApproval before they are requested or imported, unless
+	// another policy applying to the namespace permits them without one.
+	// Approvals are only trusted when the controller runs with the
+	// --certificate-approval-webhook flag; otherwise these certificates are
+	// not requested or imported at all.
+	RequireApproval bool `json:"requireApproval,omitempty"`
+}
+
+// CertificatePolicy is the Schema for the CertificatePolicies API. Once a
+// CertificatePolicy exists, a Certificate may only request or import a
+// certificate that one of the policies applying to its namespace permits.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+type CertificatePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CertificatePolicySpec `json:"spec,omitempty"`
+}
+
+// CertificatePolicyList contains a list of CertificatePolicy
+// +kubebuilder:object:root=true
+type CertificatePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificatePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificatePolicy{}, &CertificatePolicyList{})
+}
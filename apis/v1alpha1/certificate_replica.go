@@ -0,0 +1,31 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// CertificateReplica is a copy of a certificate in another region than the
+// one of the Certificate, requested or imported with the same spec.
+type CertificateReplica struct {
+	// Region the replica is in.
+	Region string `json:"region"`
+	// CertificateARN is the Amazon Resource Name (ARN) of the replica.
+	CertificateARN string `json:"certificateARN"`
+	// Status of the replica.
+	Status *string `json:"status,omitempty"`
+	// FailureReason is the reason the replica could not be issued, if its
+	// Status is FAILED.
+	FailureReason *string `json:"failureReason,omitempty"`
+	// DomainValidations contains information about the validation of each
+	// domain name of the replica.
+	DomainValidations []*DomainValidation `json:"domainValidations,omitempty"`
+}
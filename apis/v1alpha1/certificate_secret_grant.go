@@ -0,0 +1,56 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateSecretGrantSpec defines which Certificates in other namespaces
+// the controller may access Secrets in the namespace of the grant for.
+type CertificateSecretGrantSpec struct {
+	// Namespaces are the namespaces of the Certificates the grant applies
+	// to.
+	Namespaces []string `json:"namespaces"`
+	// SecretNames are the names of the Secrets the grant applies to. It
+	// applies to all Secrets in its namespace when empty.
+	SecretNames []string `json:"secretNames,omitempty"`
+	// Access is Read to let the Certificates import from the Secrets, Write
+	// to let them export into the Secrets, or ReadWrite for both.
+	// +kubebuilder:validation:Enum=Read;Write;ReadWrite
+	Access string `json:"access"`
+}
+
+// CertificateSecretGrant is the Schema for the CertificateSecretGrants API.
+// The controller only reads or writes a Secret on behalf of a Certificate
+// in another namespace if a CertificateSecretGrant in the namespace of the
+// Secret permits it.
+// +kubebuilder:object:root=true
+type CertificateSecretGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CertificateSecretGrantSpec `json:"spec,omitempty"`
+}
+
+// CertificateSecretGrantList contains a list of CertificateSecretGrant
+// +kubebuilder:object:root=true
+type CertificateSecretGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateSecretGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&CertificateSecretGrant{}, &CertificateSecretGrantList{})
+}
@@ -15,6 +15,19 @@
 
 package v1alpha1
 
+type CertificateExport string
+
+const (
+	CertificateExport_DISABLED CertificateExport = "DISABLED"
+	CertificateExport_ENABLED  CertificateExport = "ENABLED"
+)
+
+type CertificateManagedBy string
+
+const (
+	CertificateManagedBy_CLOUDFRONT CertificateManagedBy = "CLOUDFRONT"
+)
+
 type CertificateStatus_SDK string
 
 const (
@@ -151,6 +164,7 @@ const (
 	RevocationReason_PRIVILEGE_WITHDRAWN    RevocationReason = "PRIVILEGE_WITHDRAWN"
 	RevocationReason_REMOVE_FROM_CRL        RevocationReason = "REMOVE_FROM_CRL"
 	RevocationReason_SUPERCEDED             RevocationReason = "SUPERCEDED"
+	RevocationReason_SUPERSEDED             RevocationReason = "SUPERSEDED"
 	RevocationReason_UNSPECIFIED            RevocationReason = "UNSPECIFIED"
 )
 
@@ -171,5 +185,6 @@ type ValidationMethod string
 
 const (
 	ValidationMethod_DNS   ValidationMethod = "DNS"
+	ValidationMethod_HTTP  ValidationMethod = "HTTP"
 	ValidationMethod_EMAIL ValidationMethod = "EMAIL"
 )
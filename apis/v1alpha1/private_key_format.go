@@ -0,0 +1,29 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// PrivateKeyFormat is the encoding of the private keys of exported
+// certificates.
+// +kubebuilder:validation:Enum=PKCS8;Traditional
+type PrivateKeyFormat string
+
+const (
+	// PrivateKeyFormatPKCS8 encodes private keys of any type as PKCS #8
+	// "PRIVATE KEY" blocks. This is the default.
+	PrivateKeyFormatPKCS8 PrivateKeyFormat = "PKCS8"
+	// PrivateKeyFormatTraditional encodes RSA private keys as PKCS #1
+	// "RSA PRIVATE KEY" blocks and EC private keys as SEC 1
+	// "EC PRIVATE KEY" blocks.
+	PrivateKeyFormatTraditional PrivateKeyFormat = "Traditional"
+)
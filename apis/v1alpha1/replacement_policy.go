@@ -0,0 +1,28 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// ReplacementPolicy controls what happens when a field of a requested
+// certificate that ACM cannot update is changed.
+// +kubebuilder:validation:Enum=Reject;Replace
+type ReplacementPolicy string
+
+const (
+	// ReplacementPolicyReject sets a Terminal condition when a field that ACM
+	// cannot update is changed. This is the default.
+	ReplacementPolicyReject ReplacementPolicy = "Reject"
+	// ReplacementPolicyReplace requests a new certificate when a field that
+	// ACM cannot update is changed.
+	ReplacementPolicyReplace ReplacementPolicy = "Replace"
+)
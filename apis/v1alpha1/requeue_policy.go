@@ -0,0 +1,32 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RequeuePolicy controls how often the controller describes a certificate
+// in ACM. Intervals that are not set keep their default.
+type RequeuePolicy struct {
+	// Pending is the interval while the certificate, its renewal or its
+	// replacement is pending validation. Defaults to 1m.
+	Pending *metav1.Duration `json:"pending,omitempty"`
+	// Renewal is the interval while ACM is renewing the certificate.
+	// Defaults to 5m.
+	Renewal *metav1.Duration `json:"renewal,omitempty"`
+	// Settled is the interval once the certificate is issued. It shrinks as
+	// the certificate gets within 30 days of expiring. Defaults to 6h.
+	Settled *metav1.Duration `json:"settled,omitempty"`
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// TLSSecretReference refers to a Secret that holds a certificate, its private
+// key and optionally its CA in the standard kubernetes.io/tls layout, as
+// written by cert-manager and Vault.
+type TLSSecretReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+	// Namespace of the Secret. Defaults to the namespace of the Certificate. A
+	// Secret in another namespace must be permitted to be read by a
+	// CertificateSecretGrant in that namespace.
+	Namespace string `json:"namespace,omitempty"`
+}
@@ -44,6 +44,7 @@ type CertificateDetail struct {
 	Issuer                  *string             `json:"issuer,omitempty"`
 	KeyAlgorithm            *string             `json:"keyAlgorithm,omitempty"`
 	KeyUsages               []*KeyUsage         `json:"keyUsages,omitempty"`
+	ManagedBy               *string             `json:"managedBy,omitempty"`
 	NotAfter                *metav1.Time        `json:"notAfter,omitempty"`
 	NotBefore               *metav1.Time        `json:"notBefore,omitempty"`
 	// Structure that contains options for your certificate. Currently, you can
@@ -78,6 +79,7 @@ type CertificateDetail struct {
 // For general information, see Certificate Transparency Logging (https://docs.aws.amazon.com/acm/latest/userguide/acm-concepts.html#concept-transparency).
 type CertificateOptions struct {
 	CertificateTransparencyLoggingPreference *string `json:"certificateTransparencyLoggingPreference,omitempty"`
+	Export                                   *string `json:"export,omitempty"`
 }
 
 // This structure is returned in the response object of ListCertificates action.
@@ -85,6 +87,7 @@ type CertificateSummary struct {
 	CertificateARN                       *string      `json:"certificateARN,omitempty"`
 	CreatedAt                            *metav1.Time `json:"createdAt,omitempty"`
 	DomainName                           *string      `json:"domainName,omitempty"`
+	ExportOption                         *string      `json:"exportOption,omitempty"`
 	Exported                             *bool        `json:"exported,omitempty"`
 	ExtendedKeyUsages                    []*string    `json:"extendedKeyUsages,omitempty"`
 	HasAdditionalSubjectAlternativeNames *bool        `json:"hasAdditionalSubjectAlternativeNames,omitempty"`
@@ -93,6 +96,7 @@ type CertificateSummary struct {
 	IssuedAt                             *metav1.Time `json:"issuedAt,omitempty"`
 	KeyAlgorithm                         *string      `json:"keyAlgorithm,omitempty"`
 	KeyUsages                            []*string    `json:"keyUsages,omitempty"`
+	ManagedBy                            *string      `json:"managedBy,omitempty"`
 	NotAfter                             *metav1.Time `json:"notAfter,omitempty"`
 	NotBefore                            *metav1.Time `json:"notBefore,omitempty"`
 	RenewalEligibility                   *string      `json:"renewalEligibility,omitempty"`
@@ -132,9 +136,19 @@ type ExtendedKeyUsage struct {
 // This structure can be used in the ListCertificates action to filter the output
 // of the certificate list.
 type Filters struct {
+	ExportOption     *string   `json:"exportOption,omitempty"`
 	ExtendedKeyUsage []*string `json:"extendedKeyUsage,omitempty"`
 	KeyTypes         []*string `json:"keyTypes,omitempty"`
 	KeyUsage         []*string `json:"keyUsage,omitempty"`
+	ManagedBy        *string   `json:"managedBy,omitempty"`
+}
+
+// Contains information for HTTP-based domain validation of certificates requested
+// through Amazon CloudFront and issued by ACM. This field exists only when the
+// certificate type is AMAZON_ISSUED and the validation method is HTTP.
+type HTTPRedirect struct {
+	RedirectFrom *string `json:"redirectFrom,omitempty"`
+	RedirectTo   *string `json:"redirectTo,omitempty"`
 }
 
 // The Key Usage X.509 v3 extension defines the purpose of the public key contained
@@ -0,0 +1,27 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// ValidationRetryPolicy controls whether a requested certificate that fails
+// validation is requested again.
+type ValidationRetryPolicy struct {
+	// MaxAttempts is the number of times the certificate is requested again
+	// after failing validation.
+	// +kubebuilder:validation:Minimum=1
+	MaxAttempts int64 `json:"maxAttempts"`
+	// FailureReasons are the reasons a certificate with status FAILED is
+	// requested again for, e.g. CAA_ERROR. A certificate with status
+	// VALIDATION_TIMED_OUT is always requested again.
+	FailureReasons []string `json:"failureReasons,omitempty"`
+}
@@ -21,9 +21,25 @@ package v1alpha1
 
 import (
 	corev1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerCertificateReference) DeepCopyInto(out *CertManagerCertificateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerCertificateReference.
+func (in *CertManagerCertificateReference) DeepCopy() *CertManagerCertificateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerCertificateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Certificate) DeepCopyInto(out *Certificate) {
 	*out = *in
@@ -51,6 +67,263 @@ func (in *Certificate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateApproval) DeepCopyInto(out *CertificateApproval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateApproval.
+func (in *CertificateApproval) DeepCopy() *CertificateApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateApproval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateApprovalList) DeepCopyInto(out *CertificateApprovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateApprovalList.
+func (in *CertificateApprovalList) DeepCopy() *CertificateApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateApprovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateApprovalRecord) DeepCopyInto(out *CertificateApprovalRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateApprovalRecord.
+func (in *CertificateApprovalRecord) DeepCopy() *CertificateApprovalRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateApprovalRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateApprovalSpec) DeepCopyInto(out *CertificateApprovalSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateApprovalSpec.
+func (in *CertificateApprovalSpec) DeepCopy() *CertificateApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateClass) DeepCopyInto(out *CertificateClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateClass.
+func (in *CertificateClass) DeepCopy() *CertificateClass {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateClassList) DeepCopyInto(out *CertificateClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificateClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateClassList.
+func (in *CertificateClassList) DeepCopy() *CertificateClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateClassSpec) DeepCopyInto(out *CertificateClassSpec) {
+	*out = *in
+	if in.CertificateAuthorityARN != nil {
+		in, out := &in.CertificateAuthorityARN, &out.CertificateAuthorityARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.CertificateAuthorityRef != nil {
+		in, out := &in.CertificateAuthorityRef, &out.CertificateAuthorityRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExportKeyFormat != nil {
+		in, out := &in.ExportKeyFormat, &out.ExportKeyFormat
+		*out = new(PrivateKeyFormat)
+		**out = **in
+	}
+	if in.KeyAlgorithm != nil {
+		in, out := &in.KeyAlgorithm, &out.KeyAlgorithm
+		*out = new(string)
+		**out = **in
+	}
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(CertificateOptions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplacementPolicy != nil {
+		in, out := &in.ReplacementPolicy, &out.ReplacementPolicy
+		*out = new(ReplacementPolicy)
+		**out = **in
+	}
+	if in.RequeuePolicy != nil {
+		in, out := &in.RequeuePolicy, &out.RequeuePolicy
+		*out = new(RequeuePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]*Tag, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Tag)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ValidationRetryPolicy != nil {
+		in, out := &in.ValidationRetryPolicy, &out.ValidationRetryPolicy
+		*out = new(ValidationRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateClassSpec.
+func (in *CertificateClassSpec) DeepCopy() *CertificateClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateConsumer) DeepCopyInto(out *CertificateConsumer) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateConsumer.
+func (in *CertificateConsumer) DeepCopy() *CertificateConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateConsumerStatus) DeepCopyInto(out *CertificateConsumerStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateConsumerStatus.
+func (in *CertificateConsumerStatus) DeepCopy() *CertificateConsumerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateConsumerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateGatewayListener) DeepCopyInto(out *CertificateGatewayListener) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateGatewayListener.
+func (in *CertificateGatewayListener) DeepCopy() *CertificateGatewayListener {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateGatewayListener)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateDetail) DeepCopyInto(out *CertificateDetail) {
 	*out = *in
@@ -140,6 +413,11 @@ func (in *CertificateDetail) DeepCopyInto(out *CertificateDetail) {
 			}
 		}
 	}
+	if in.ManagedBy != nil {
+		in, out := &in.ManagedBy, &out.ManagedBy
+		*out = new(string)
+		**out = **in
+	}
 	if in.NotAfter != nil {
 		in, out := &in.NotAfter, &out.NotAfter
 		*out = (*in).DeepCopy()
@@ -158,94 +436,311 @@ func (in *CertificateDetail) DeepCopyInto(out *CertificateDetail) {
 		*out = new(string)
 		**out = **in
 	}
-	if in.RenewalSummary != nil {
-		in, out := &in.RenewalSummary, &out.RenewalSummary
-		*out = new(RenewalSummary)
+	if in.RenewalSummary != nil {
+		in, out := &in.RenewalSummary, &out.RenewalSummary
+		*out = new(RenewalSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevocationReason != nil {
+		in, out := &in.RevocationReason, &out.RevocationReason
+		*out = new(string)
+		**out = **in
+	}
+	if in.RevokedAt != nil {
+		in, out := &in.RevokedAt, &out.RevokedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Serial != nil {
+		in, out := &in.Serial, &out.Serial
+		*out = new(string)
+		**out = **in
+	}
+	if in.SignatureAlgorithm != nil {
+		in, out := &in.SignatureAlgorithm, &out.SignatureAlgorithm
+		*out = new(string)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(string)
+		**out = **in
+	}
+	if in.Subject != nil {
+		in, out := &in.Subject, &out.Subject
+		*out = new(string)
+		**out = **in
+	}
+	if in.SubjectAlternativeNames != nil {
+		in, out := &in.SubjectAlternativeNames, &out.SubjectAlternativeNames
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateDetail.
+func (in *CertificateDetail) DeepCopy() *CertificateDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateList) DeepCopyInto(out *CertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Certificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateList.
+func (in *CertificateList) DeepCopy() *CertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateOptions) DeepCopyInto(out *CertificateOptions) {
+	*out = *in
+	if in.CertificateTransparencyLoggingPreference != nil {
+		in, out := &in.CertificateTransparencyLoggingPreference, &out.CertificateTransparencyLoggingPreference
+		*out = new(string)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateOptions.
+func (in *CertificateOptions) DeepCopy() *CertificateOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePolicy) DeepCopyInto(out *CertificatePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePolicy.
+func (in *CertificatePolicy) DeepCopy() *CertificatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificatePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePolicyList) DeepCopyInto(out *CertificatePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CertificatePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePolicyList.
+func (in *CertificatePolicyList) DeepCopy() *CertificatePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificatePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificatePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificatePolicySpec) DeepCopyInto(out *CertificatePolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.RevocationReason != nil {
-		in, out := &in.RevocationReason, &out.RevocationReason
-		*out = new(string)
-		**out = **in
+	if in.DomainPatterns != nil {
+		in, out := &in.DomainPatterns, &out.DomainPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.RevokedAt != nil {
-		in, out := &in.RevokedAt, &out.RevokedAt
-		*out = (*in).DeepCopy()
+	if in.CertificateAuthorityARNs != nil {
+		in, out := &in.CertificateAuthorityARNs, &out.CertificateAuthorityARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Serial != nil {
-		in, out := &in.Serial, &out.Serial
-		*out = new(string)
-		**out = **in
+	if in.KeyAlgorithms != nil {
+		in, out := &in.KeyAlgorithms, &out.KeyAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.SignatureAlgorithm != nil {
-		in, out := &in.SignatureAlgorithm, &out.SignatureAlgorithm
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatePolicySpec.
+func (in *CertificatePolicySpec) DeepCopy() *CertificatePolicySpec {
+	if in == nil {
+		return nil
 	}
+	out := new(CertificatePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateReplica) DeepCopyInto(out *CertificateReplica) {
+	*out = *in
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(string)
 		**out = **in
 	}
-	if in.Subject != nil {
-		in, out := &in.Subject, &out.Subject
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
 		*out = new(string)
 		**out = **in
 	}
-	if in.SubjectAlternativeNames != nil {
-		in, out := &in.SubjectAlternativeNames, &out.SubjectAlternativeNames
-		*out = make([]*string, len(*in))
+	if in.DomainValidations != nil {
+		in, out := &in.DomainValidations, &out.DomainValidations
+		*out = make([]*DomainValidation, len(*in))
 		for i := range *in {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
-				*out = new(string)
-				**out = **in
+				*out = new(DomainValidation)
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
-	if in.Type != nil {
-		in, out := &in.Type, &out.Type
-		*out = new(string)
-		**out = **in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateReplica.
+func (in *CertificateReplica) DeepCopy() *CertificateReplica {
+	if in == nil {
+		return nil
 	}
+	out := new(CertificateReplica)
+	in.DeepCopyInto(out)
+	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateDetail.
-func (in *CertificateDetail) DeepCopy() *CertificateDetail {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateSecretGrant) DeepCopyInto(out *CertificateSecretGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecretGrant.
+func (in *CertificateSecretGrant) DeepCopy() *CertificateSecretGrant {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificateDetail)
+	out := new(CertificateSecretGrant)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CertificateSecretGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertificateList) DeepCopyInto(out *CertificateList) {
+func (in *CertificateSecretGrantList) DeepCopyInto(out *CertificateSecretGrantList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Certificate, len(*in))
+		*out = make([]CertificateSecretGrant, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateList.
-func (in *CertificateList) DeepCopy() *CertificateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecretGrantList.
+func (in *CertificateSecretGrantList) DeepCopy() *CertificateSecretGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificateList)
+	out := new(CertificateSecretGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *CertificateList) DeepCopyObject() runtime.Object {
+func (in *CertificateSecretGrantList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -253,21 +748,26 @@ func (in *CertificateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *CertificateOptions) DeepCopyInto(out *CertificateOptions) {
+func (in *CertificateSecretGrantSpec) DeepCopyInto(out *CertificateSecretGrantSpec) {
 	*out = *in
-	if in.CertificateTransparencyLoggingPreference != nil {
-		in, out := &in.CertificateTransparencyLoggingPreference, &out.CertificateTransparencyLoggingPreference
-		*out = new(string)
-		**out = **in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretNames != nil {
+		in, out := &in.SecretNames, &out.SecretNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateOptions.
-func (in *CertificateOptions) DeepCopy() *CertificateOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSecretGrantSpec.
+func (in *CertificateSecretGrantSpec) DeepCopy() *CertificateSecretGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(CertificateOptions)
+	out := new(CertificateSecretGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -275,6 +775,11 @@ func (in *CertificateOptions) DeepCopy() *CertificateOptions {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 	*out = *in
+	if in.CertManagerCertificateRef != nil {
+		in, out := &in.CertManagerCertificateRef, &out.CertManagerCertificateRef
+		*out = new(CertManagerCertificateReference)
+		**out = **in
+	}
 	if in.Certificate != nil {
 		in, out := &in.Certificate, &out.Certificate
 		*out = new(corev1alpha1.SecretKeyReference)
@@ -300,6 +805,22 @@ func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 		*out = new(corev1alpha1.SecretKeyReference)
 		**out = **in
 	}
+	if in.CertificateClassName != nil {
+		in, out := &in.CertificateClassName, &out.CertificateClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]*CertificateConsumer, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CertificateConsumer)
+				**out = **in
+			}
+		}
+	}
 	if in.DomainName != nil {
 		in, out := &in.DomainName, &out.DomainName
 		*out = new(string)
@@ -316,11 +837,21 @@ func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 			}
 		}
 	}
+	if in.ExportKeyFormat != nil {
+		in, out := &in.ExportKeyFormat, &out.ExportKeyFormat
+		*out = new(PrivateKeyFormat)
+		**out = **in
+	}
 	if in.ExportTo != nil {
 		in, out := &in.ExportTo, &out.ExportTo
 		*out = new(corev1alpha1.SecretKeyReference)
 		**out = **in
 	}
+	if in.ImportFrom != nil {
+		in, out := &in.ImportFrom, &out.ImportFrom
+		*out = new(TLSSecretReference)
+		**out = **in
+	}
 	if in.KeyAlgorithm != nil {
 		in, out := &in.KeyAlgorithm, &out.KeyAlgorithm
 		*out = new(string)
@@ -336,6 +867,27 @@ func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 		*out = new(corev1alpha1.SecretKeyReference)
 		**out = **in
 	}
+	if in.ReplacementPolicy != nil {
+		in, out := &in.ReplacementPolicy, &out.ReplacementPolicy
+		*out = new(ReplacementPolicy)
+		**out = **in
+	}
+	if in.ReplicaRegions != nil {
+		in, out := &in.ReplicaRegions, &out.ReplicaRegions
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.RequeuePolicy != nil {
+		in, out := &in.RequeuePolicy, &out.RequeuePolicy
+		*out = new(RequeuePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SubjectAlternativeNames != nil {
 		in, out := &in.SubjectAlternativeNames, &out.SubjectAlternativeNames
 		*out = make([]*string, len(*in))
@@ -358,6 +910,11 @@ func (in *CertificateSpec) DeepCopyInto(out *CertificateSpec) {
 			}
 		}
 	}
+	if in.ValidationRetryPolicy != nil {
+		in, out := &in.ValidationRetryPolicy, &out.ValidationRetryPolicy
+		*out = new(ValidationRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateSpec.
@@ -389,6 +946,22 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 			}
 		}
 	}
+	if in.AnnotatedConsumers != nil {
+		in, out := &in.AnnotatedConsumers, &out.AnnotatedConsumers
+		*out = make([]*CertificateConsumerStatus, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CertificateConsumerStatus)
+				**out = **in
+			}
+		}
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(CertificateApprovalRecord)
+		**out = **in
+	}
 	if in.CreatedAt != nil {
 		in, out := &in.CreatedAt, &out.CreatedAt
 		*out = (*in).DeepCopy()
@@ -404,6 +977,17 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 			}
 		}
 	}
+	if in.DryRunPlan != nil {
+		in, out := &in.DryRunPlan, &out.DryRunPlan
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
 	if in.ExtendedKeyUsages != nil {
 		in, out := &in.ExtendedKeyUsages, &out.ExtendedKeyUsages
 		*out = make([]*ExtendedKeyUsage, len(*in))
@@ -420,6 +1004,27 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.GatewayListeners != nil {
+		in, out := &in.GatewayListeners, &out.GatewayListeners
+		*out = make([]*CertificateGatewayListener, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CertificateGatewayListener)
+				**out = **in
+			}
+		}
+	}
+	if in.ImportSourceResourceVersion != nil {
+		in, out := &in.ImportSourceResourceVersion, &out.ImportSourceResourceVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.ImportSourceSerial != nil {
+		in, out := &in.ImportSourceSerial, &out.ImportSourceSerial
+		*out = new(string)
+		**out = **in
+	}
 	if in.ImportedAt != nil {
 		in, out := &in.ImportedAt, &out.ImportedAt
 		*out = (*in).DeepCopy()
@@ -473,6 +1078,44 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 		*out = new(RenewalSummary)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplacementCertificateARN != nil {
+		in, out := &in.ReplacementCertificateARN, &out.ReplacementCertificateARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.ReplacementDomainValidations != nil {
+		in, out := &in.ReplacementDomainValidations, &out.ReplacementDomainValidations
+		*out = make([]*DomainValidation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(DomainValidation)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]*CertificateReplica, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CertificateReplica)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.RetiredCertificateARNs != nil {
+		in, out := &in.RetiredCertificateARNs, &out.RetiredCertificateARNs
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
 	if in.RevocationReason != nil {
 		in, out := &in.RevocationReason, &out.RevocationReason
 		*out = new(string)
@@ -507,6 +1150,15 @@ func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ValidationExpiresAt != nil {
+		in, out := &in.ValidationExpiresAt, &out.ValidationExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ValidationRetries != nil {
+		in, out := &in.ValidationRetries, &out.ValidationRetries
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
@@ -536,6 +1188,11 @@ func (in *CertificateSummary) DeepCopyInto(out *CertificateSummary) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ExportOption != nil {
+		in, out := &in.ExportOption, &out.ExportOption
+		*out = new(string)
+		**out = **in
+	}
 	if in.Exported != nil {
 		in, out := &in.Exported, &out.Exported
 		*out = new(bool)
@@ -586,6 +1243,11 @@ func (in *CertificateSummary) DeepCopyInto(out *CertificateSummary) {
 			}
 		}
 	}
+	if in.ManagedBy != nil {
+		in, out := &in.ManagedBy, &out.ManagedBy
+		*out = new(string)
+		**out = **in
+	}
 	if in.NotAfter != nil {
 		in, out := &in.NotAfter, &out.NotAfter
 		*out = (*in).DeepCopy()
@@ -740,6 +1402,11 @@ func (in *ExtendedKeyUsage) DeepCopy() *ExtendedKeyUsage {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Filters) DeepCopyInto(out *Filters) {
 	*out = *in
+	if in.ExportOption != nil {
+		in, out := &in.ExportOption, &out.ExportOption
+		*out = new(string)
+		**out = **in
+	}
 	if in.ExtendedKeyUsage != nil {
 		in, out := &in.ExtendedKeyUsage, &out.ExtendedKeyUsage
 		*out = make([]*string, len(*in))
@@ -773,6 +1440,11 @@ func (in *Filters) DeepCopyInto(out *Filters) {
 			}
 		}
 	}
+	if in.ManagedBy != nil {
+		in, out := &in.ManagedBy, &out.ManagedBy
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filters.
@@ -785,6 +1457,31 @@ func (in *Filters) DeepCopy() *Filters {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRedirect) DeepCopyInto(out *HTTPRedirect) {
+	*out = *in
+	if in.RedirectFrom != nil {
+		in, out := &in.RedirectFrom, &out.RedirectFrom
+		*out = new(string)
+		**out = **in
+	}
+	if in.RedirectTo != nil {
+		in, out := &in.RedirectTo, &out.RedirectTo
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRedirect.
+func (in *HTTPRedirect) DeepCopy() *HTTPRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyUsage) DeepCopyInto(out *KeyUsage) {
 	*out = *in
@@ -845,6 +1542,36 @@ func (in *RenewalSummary) DeepCopy() *RenewalSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequeuePolicy) DeepCopyInto(out *RequeuePolicy) {
+	*out = *in
+	if in.Pending != nil {
+		in, out := &in.Pending, &out.Pending
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Renewal != nil {
+		in, out := &in.Renewal, &out.Renewal
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Settled != nil {
+		in, out := &in.Settled, &out.Settled
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequeuePolicy.
+func (in *RequeuePolicy) DeepCopy() *RequeuePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RequeuePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceRecord) DeepCopyInto(out *ResourceRecord) {
 	*out = *in
@@ -875,6 +1602,21 @@ func (in *ResourceRecord) DeepCopy() *ResourceRecord {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSSecretReference) DeepCopyInto(out *TLSSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSSecretReference.
+func (in *TLSSecretReference) DeepCopy() *TLSSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tag) DeepCopyInto(out *Tag) {
 	*out = *in
@@ -899,3 +1641,23 @@ func (in *Tag) DeepCopy() *Tag {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationRetryPolicy) DeepCopyInto(out *ValidationRetryPolicy) {
+	*out = *in
+	if in.FailureReasons != nil {
+		in, out := &in.FailureReasons, &out.FailureReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationRetryPolicy.
+func (in *ValidationRetryPolicy) DeepCopy() *ValidationRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Command acm-emulator serves an in-memory ACM over HTTP, so that the
+// controller and the e2e tests can run without AWS credentials.
+//
+// Point the controller at it with --aws-endpoint-url,
+// --aws-identity-endpoint-url and --allow-unsafe-aws-endpoint-urls, and the
+// e2e tests with the AWS_ENDPOINT_URL environment variable, as
+// "make run-controller-emulator" and "make test-e2e-emulator" do. Requests
+// are not authenticated, so any credentials are accepted.
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/aws-controllers-k8s/acm-controller/pkg/fakeacm"
+)
+
+func main() {
+	var (
+		addr string
+		opts fakeacm.Options
+	)
+	flag.StringVar(&addr, "listen-addr", "localhost:9700", "The address the emulator listens on.")
+	flag.StringVar(&opts.AccountID, "account-id", "111122223333", "The AWS account ID used in certificate ARNs.")
+	flag.StringVar(&opts.Region, "region", "us-west-2", "The AWS region used in certificate ARNs.")
+	flag.DurationVar(&opts.ValidationDelay, "validation-delay", 30*time.Second,
+		"How long public certificates are pending validation before they are issued. "+
+			"A negative delay keeps them pending.")
+	flag.StringSliceVar(&opts.FailDomains, "fail-domains", nil,
+		"Domains, including their subdomains, whose public certificates fail validation.")
+	flag.DurationVar(&opts.Validity, "validity", 395*24*time.Hour, "How long issued certificates are valid for.")
+	flag.DurationVar(&opts.RenewBefore, "renew-before", 0,
+		"How long before they expire requested certificates are renewed. Zero disables renewals.")
+	flag.Parse()
+
+	server, err := fakeacm.New(opts)
+	if err != nil {
+		log.Fatalf("creating emulator: %v", err)
+	}
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           logRequests(server),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("ACM emulator listening on %s", addr)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("serving: %v", err)
+	}
+}
+
+// statusRecorder records the status code written to a ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs the operation and response status of every request.
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, req)
+		log.Printf("%s %d", req.Header.Get("X-Amz-Target"), rec.status)
+	})
+}
@@ -173,6 +173,24 @@ func main() {
 		}
 	}
 
+	// Resource manager factories that access Kubernetes objects other than
+	// their resources are bound to the controller manager for its clients.
+	for _, mf := range managerFactories {
+		binder, ok := mf.(interface {
+			BindControllerManager(ctrlrt.Manager, ackcfg.Config) error
+		})
+		if !ok {
+			continue
+		}
+		if err = binder.BindControllerManager(mgr, ackCfg); err != nil {
+			setupLog.Error(
+				err, "unable to bind resource manager factory to controller manager",
+				"aws.service", awsServiceAlias,
+			)
+			os.Exit(1)
+		}
+	}
+
 	if err = sc.BindControllerManager(mgr, ackCfg); err != nil {
 		setupLog.Error(
 			err, "unable bind to controller manager to service controller",
@@ -56,11 +56,19 @@ rules:
   resources:
   - configmaps
   - secrets
+  - services
   verbs:
   - get
   - list
   - patch
   - watch
+- apiGroups:
+  - ""
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
 - apiGroups:
   - ""
   resources:
@@ -69,6 +77,30 @@ rules:
   - get
   - list
   - watch
+- apiGroups:
+  - acm.services.k8s.aws
+  resources:
+  - certificateapprovals
+  verbs:
+  - list
+  - patch
+  - watch
+- apiGroups:
+  - acm.services.k8s.aws
+  resources:
+  - certificateclasses
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - acm.services.k8s.aws
+  resources:
+  - certificatepolicies
+  - certificatesecretgrants
+  verbs:
+  - list
+  - watch
 - apiGroups:
   - acm.services.k8s.aws
   resources:
@@ -97,6 +129,39 @@ rules:
   verbs:
   - get
   - list
+- apiGroups:
+  - cert-manager.io
+  resources:
+  - certificates
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - gateway.networking.k8s.io
+  resources:
+  - gateways
+  verbs:
+  - get
+  - list
+  - patch
+  - watch
+- apiGroups:
+  - gateway.networking.k8s.io
+  resources:
+  - referencegrants
+  verbs:
+  - list
+  - watch
+- apiGroups:
+  - networking.k8s.io
+  resources:
+  - ingresses
+  verbs:
+  - get
+  - list
+  - patch
+  - watch
 - apiGroups:
   - services.k8s.aws
   resources:
@@ -0,0 +1,70 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeacm
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// getCallerIdentityAction is the STS action the controller calls at
+	// startup to look up its AWS account ID.
+	getCallerIdentityAction = "GetCallerIdentity"
+	stsNamespace            = "https://sts.amazonaws.com/doc/2011-06-15/"
+)
+
+// getCallerIdentityResponse is the XML response of the STS
+// GetCallerIdentity action.
+type getCallerIdentityResponse struct {
+	XMLName xml.Name `xml:"GetCallerIdentityResponse"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Result  struct {
+		Account string `xml:"Account"`
+		Arn     string `xml:"Arn"`
+		UserID  string `xml:"UserId"`
+	} `xml:"GetCallerIdentityResult"`
+	RequestID string `xml:"ResponseMetadata>RequestId"`
+}
+
+// isIdentityRequest returns true if the supplied request is an STS query
+// protocol request rather than an ACM one.
+func isIdentityRequest(req *http.Request) bool {
+	return req.Header.Get("X-Amz-Target") == "" &&
+		strings.HasPrefix(req.Header.Get("Content-Type"), "application/x-www-form-urlencoded")
+}
+
+// serveIdentity serves the STS GetCallerIdentity action with the account ID
+// of the Server, so that the controller can be pointed at the Server with
+// --aws-identity-endpoint-url too.
+func (s *Server) serveIdentity(w http.ResponseWriter, req *http.Request) {
+	if err := req.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "SerializationException", err.Error())
+		return
+	}
+	if action := req.PostForm.Get("Action"); action != getCallerIdentityAction {
+		writeError(w, http.StatusBadRequest, "InvalidAction",
+			fmt.Sprintf("Action %q is not supported", action))
+		return
+	}
+	resp := getCallerIdentityResponse{Xmlns: stsNamespace, RequestID: "fakeacm"}
+	resp.Result.Account = s.opts.AccountID
+	resp.Result.Arn = fmt.Sprintf("arn:aws:iam::%s:user/fakeacm", s.opts.AccountID)
+	resp.Result.UserID = "fakeacm"
+	w.Header().Set("Content-Type", "text/xml")
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(resp)
+}
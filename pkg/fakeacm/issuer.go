@@ -0,0 +1,234 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeacm
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// issuer is the CA that signs the certificates requested from a Server.
+type issuer struct {
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+}
+
+// newIssuer returns an issuer with a new self-signed CA certificate.
+func newIssuer(now time.Time) (*issuer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Fake ACM"}, CommonName: "Fake ACM Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &issuer{cert: cert, certPEM: encodeCertificate(cert), key: key}, nil
+}
+
+// issue returns a new certificate for the supplied domains, signed by the
+// issuer, and its private key.
+func (i *issuer) issue(
+	domainName string,
+	subjectAlternativeNames []string,
+	keyAlgorithm svcsdktypes.KeyAlgorithm,
+	notBefore time.Time,
+	notAfter time.Time,
+) (*x509.Certificate, crypto.Signer, error) {
+	key, err := generateKey(keyAlgorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: domainName},
+		DNSNames:     subjectAlternativeNames,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if _, isRSA := key.(*rsa.PrivateKey); isRSA {
+		template.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, i.cert, key.Public(), i.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+// generateKey returns a new private key of the supplied algorithm.
+func generateKey(keyAlgorithm svcsdktypes.KeyAlgorithm) (crypto.Signer, error) {
+	switch keyAlgorithm {
+	case svcsdktypes.KeyAlgorithmRsa1024:
+		return rsa.GenerateKey(rand.Reader, 1024)
+	case svcsdktypes.KeyAlgorithmRsa2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case svcsdktypes.KeyAlgorithmRsa3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case svcsdktypes.KeyAlgorithmRsa4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case svcsdktypes.KeyAlgorithmEcPrime256v1:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case svcsdktypes.KeyAlgorithmEcSecp384r1:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case svcsdktypes.KeyAlgorithmEcSecp521r1:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key algorithm %s", keyAlgorithm)
+	}
+}
+
+// describedKeyAlgorithm returns the key algorithm of the supplied public
+// key the way DescribeCertificate reports it, e.g. RSA-2048 rather than
+// the RSA_2048 RequestCertificate accepts.
+func describedKeyAlgorithm(pub crypto.PublicKey) svcsdktypes.KeyAlgorithm {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return svcsdktypes.KeyAlgorithm(fmt.Sprintf("RSA-%d", pub.N.BitLen()))
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return "EC-prime256v1"
+		case elliptic.P384():
+			return "EC-secp384r1"
+		case elliptic.P521():
+			return "EC-secp521r1"
+		}
+	}
+	return ""
+}
+
+// setCertificateDetails sets the fields of detail that are read from the
+// supplied certificate.
+func setCertificateDetails(detail *svcsdktypes.CertificateDetail, cert *x509.Certificate) {
+	detail.Serial = aws.String(formatSerial(cert.SerialNumber))
+	detail.Subject = aws.String(cert.Subject.String())
+	detail.Issuer = aws.String(cert.Issuer.CommonName)
+	detail.NotBefore = aws.Time(cert.NotBefore)
+	detail.NotAfter = aws.Time(cert.NotAfter)
+	detail.KeyAlgorithm = describedKeyAlgorithm(cert.PublicKey)
+	detail.SignatureAlgorithm = aws.String(strings.ToUpper(cert.SignatureAlgorithm.String()))
+}
+
+// newSerialNumber returns a random certificate serial number.
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// formatSerial returns the supplied serial number the way ACM formats it:
+// colon separated, lower case hex bytes.
+func formatSerial(serial *big.Int) string {
+	b := serial.Bytes()
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = fmt.Sprintf("%02x", b[i])
+	}
+	return strings.Join(parts, ":")
+}
+
+// encodeCertificate returns the supplied certificate as PEM data.
+func encodeCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+// parseCertificates returns the certificates in the supplied PEM data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// parsePrivateKey returns the unencrypted private key in the supplied PEM
+// data.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block of type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
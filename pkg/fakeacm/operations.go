@@ -0,0 +1,431 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeacm
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	pkcs8 "github.com/youmark/pkcs8"
+)
+
+const (
+	// maxTags is the maximum number of tags ACM allows on a certificate.
+	maxTags = 50
+	// minPassphraseLength is the minimum length of the passphrase of
+	// ExportCertificate.
+	minPassphraseLength = 4
+)
+
+// RequestCertificate requests a certificate. Private certificates, those
+// with a CertificateAuthorityArn, are issued right away; public ones are
+// pending validation until the validation delay has passed.
+func (s *Server) RequestCertificate(
+	ctx context.Context,
+	input *svcsdk.RequestCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.RequestCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if input.DomainName == nil || *input.DomainName == "" {
+		return nil, validationError("DomainName is required")
+	}
+	if input.IdempotencyToken != nil {
+		if arn, found := s.idempotencyTokens[*input.IdempotencyToken]; found {
+			return &svcsdk.RequestCertificateOutput{CertificateArn: aws.String(arn)}, nil
+		}
+	}
+	keyAlgorithm := input.KeyAlgorithm
+	switch keyAlgorithm {
+	case "":
+		keyAlgorithm = svcsdktypes.KeyAlgorithmRsa2048
+	case svcsdktypes.KeyAlgorithmRsa2048,
+		svcsdktypes.KeyAlgorithmEcPrime256v1,
+		svcsdktypes.KeyAlgorithmEcSecp384r1:
+	default:
+		return nil, validationError(fmt.Sprintf("KeyAlgorithm %s is not supported", keyAlgorithm))
+	}
+	if err := checkTags(nil, input.Tags); err != nil {
+		return nil, err
+	}
+	sans := []string{*input.DomainName}
+	for _, san := range input.SubjectAlternativeNames {
+		if !slices.Contains(sans, san) {
+			sans = append(sans, san)
+		}
+	}
+	options := input.Options
+	if options == nil {
+		options = &svcsdktypes.CertificateOptions{}
+	}
+	if options.CertificateTransparencyLoggingPreference == "" {
+		options.CertificateTransparencyLoggingPreference = svcsdktypes.CertificateTransparencyLoggingPreferenceEnabled
+	}
+	if options.Export == "" {
+		options.Export = svcsdktypes.CertificateExportDisabled
+	}
+
+	arn := s.newARN()
+	now := s.opts.Now()
+	c := &certificate{
+		detail: svcsdktypes.CertificateDetail{
+			CertificateArn:          aws.String(arn),
+			CertificateAuthorityArn: input.CertificateAuthorityArn,
+			CreatedAt:               aws.Time(now),
+			DomainName:              input.DomainName,
+			SubjectAlternativeNames: sans,
+			KeyAlgorithm:            svcsdktypes.KeyAlgorithm(strings.Replace(string(keyAlgorithm), "_", "-", 1)),
+			Options:                 options,
+			RenewalEligibility:      svcsdktypes.RenewalEligibilityIneligible,
+			Status:                  svcsdktypes.CertificateStatusPendingValidation,
+			Type:                    svcsdktypes.CertificateTypeAmazonIssued,
+		},
+		tags:         append([]svcsdktypes.Tag{}, input.Tags...),
+		keyAlgorithm: keyAlgorithm,
+		pendingUntil: now.Add(s.opts.ValidationDelay),
+	}
+	if input.CertificateAuthorityArn != nil {
+		c.detail.Type = svcsdktypes.CertificateTypePrivate
+		if err := s.issue(c); err != nil {
+			return nil, err
+		}
+	} else {
+		c.detail.DomainValidationOptions = domainValidations(sans, input.DomainValidationOptions)
+	}
+	s.certs[arn] = c
+	if input.IdempotencyToken != nil {
+		s.idempotencyTokens[*input.IdempotencyToken] = arn
+	}
+	return &svcsdk.RequestCertificateOutput{CertificateArn: aws.String(arn)}, nil
+}
+
+// DescribeCertificate returns the details of a certificate.
+func (s *Server) DescribeCertificate(
+	ctx context.Context,
+	input *svcsdk.DescribeCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.DescribeCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	detail := c.detail
+	detail.DomainValidationOptions = slices.Clone(detail.DomainValidationOptions)
+	detail.SubjectAlternativeNames = slices.Clone(detail.SubjectAlternativeNames)
+	detail.InUseBy = slices.Clone(detail.InUseBy)
+	if detail.Options != nil {
+		options := *detail.Options
+		detail.Options = &options
+	}
+	return &svcsdk.DescribeCertificateOutput{Certificate: &detail}, nil
+}
+
+// ImportCertificate imports a certificate, or re-imports it if
+// CertificateArn is set.
+func (s *Server) ImportCertificate(
+	ctx context.Context,
+	input *svcsdk.ImportCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.ImportCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	certs, err := parseCertificates(input.Certificate)
+	if err != nil || len(certs) != 1 {
+		return nil, validationError("The certificate field must contain exactly one PEM encoded certificate")
+	}
+	leaf := certs[0]
+	key, err := parsePrivateKey(input.PrivateKey)
+	if err != nil {
+		return nil, validationError(fmt.Sprintf("The private key is not supported: %s", err))
+	}
+	if pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(leaf.PublicKey) {
+		return nil, validationError("The private key does not match the public key in the certificate")
+	}
+
+	var c *certificate
+	if input.CertificateArn != nil {
+		if c, err = s.get(*input.CertificateArn); err != nil {
+			return nil, err
+		}
+		if c.detail.Type != svcsdktypes.CertificateTypeImported {
+			return nil, validationError("Only imported certificates can be re-imported")
+		}
+		if len(input.Tags) > 0 {
+			return nil, validationError("Tags cannot be set when re-importing a certificate")
+		}
+	} else {
+		if err := checkTags(nil, input.Tags); err != nil {
+			return nil, err
+		}
+		now := s.opts.Now()
+		c = &certificate{
+			detail: svcsdktypes.CertificateDetail{
+				CertificateArn:     aws.String(s.newARN()),
+				CreatedAt:          aws.Time(now),
+				RenewalEligibility: svcsdktypes.RenewalEligibilityIneligible,
+				Type:               svcsdktypes.CertificateTypeImported,
+				Options: &svcsdktypes.CertificateOptions{
+					CertificateTransparencyLoggingPreference: svcsdktypes.CertificateTransparencyLoggingPreferenceDisabled,
+					Export:                                   svcsdktypes.CertificateExportDisabled,
+				},
+			},
+			tags: append([]svcsdktypes.Tag{}, input.Tags...),
+		}
+		s.certs[*c.detail.CertificateArn] = c
+	}
+	domainName := leaf.Subject.CommonName
+	if domainName == "" && len(leaf.DNSNames) > 0 {
+		domainName = leaf.DNSNames[0]
+	}
+	c.detail.DomainName = aws.String(domainName)
+	c.detail.SubjectAlternativeNames = append([]string{}, leaf.DNSNames...)
+	c.detail.ImportedAt = aws.Time(s.opts.Now())
+	c.detail.Status = svcsdktypes.CertificateStatusIssued
+	setCertificateDetails(&c.detail, leaf)
+	c.certPEM = encodeCertificate(leaf)
+	c.chainPEM = append([]byte{}, input.CertificateChain...)
+	c.key = key
+	if err := s.advance(c); err != nil {
+		return nil, err
+	}
+	return &svcsdk.ImportCertificateOutput{CertificateArn: c.detail.CertificateArn}, nil
+}
+
+// ExportCertificate returns an issued private or exportable public
+// certificate, its chain, and its private key encrypted with the supplied
+// passphrase as an encrypted PKCS#8 PEM block.
+func (s *Server) ExportCertificate(
+	ctx context.Context,
+	input *svcsdk.ExportCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.ExportCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	exportable := c.detail.Type == svcsdktypes.CertificateTypePrivate ||
+		(c.detail.Options != nil && c.detail.Options.Export == svcsdktypes.CertificateExportEnabled)
+	if !exportable {
+		return nil, validationError(fmt.Sprintf("Certificate %s is not exportable", *c.detail.CertificateArn))
+	}
+	if c.detail.Status != svcsdktypes.CertificateStatusIssued {
+		return nil, &svcsdktypes.RequestInProgressException{
+			Message: aws.String(fmt.Sprintf("Certificate %s is in state %s.", *c.detail.CertificateArn, c.detail.Status)),
+		}
+	}
+	if len(input.Passphrase) < minPassphraseLength {
+		return nil, validationError(fmt.Sprintf("The passphrase must be at least %d characters long", minPassphraseLength))
+	}
+	der, err := pkcs8.MarshalPrivateKey(c.key, input.Passphrase, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &svcsdk.ExportCertificateOutput{
+		Certificate:      aws.String(string(c.certPEM)),
+		CertificateChain: aws.String(string(c.chainPEM)),
+		PrivateKey: aws.String(string(pem.EncodeToMemory(&pem.Block{
+			Type:  "ENCRYPTED PRIVATE KEY",
+			Bytes: der,
+		}))),
+	}, nil
+}
+
+// DeleteCertificate deletes a certificate that is not in use.
+func (s *Server) DeleteCertificate(
+	ctx context.Context,
+	input *svcsdk.DeleteCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.DeleteCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.detail.InUseBy) > 0 {
+		return nil, &svcsdktypes.ResourceInUseException{
+			Message: aws.String(fmt.Sprintf("Certificate %s is in use.", *c.detail.CertificateArn)),
+		}
+	}
+	delete(s.certs, *c.detail.CertificateArn)
+	return &svcsdk.DeleteCertificateOutput{}, nil
+}
+
+// UpdateCertificateOptions updates the options of a certificate.
+func (s *Server) UpdateCertificateOptions(
+	ctx context.Context,
+	input *svcsdk.UpdateCertificateOptionsInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.UpdateCertificateOptionsOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	if input.Options == nil {
+		return nil, validationError("Options is required")
+	}
+	if c.detail.Options == nil {
+		c.detail.Options = &svcsdktypes.CertificateOptions{}
+	}
+	if p := input.Options.CertificateTransparencyLoggingPreference; p != "" {
+		c.detail.Options.CertificateTransparencyLoggingPreference = p
+	}
+	if e := input.Options.Export; e != "" {
+		c.detail.Options.Export = e
+	}
+	return &svcsdk.UpdateCertificateOptionsOutput{}, nil
+}
+
+// AddTagsToCertificate adds tags to a certificate, replacing the values of
+// tags it already has.
+func (s *Server) AddTagsToCertificate(
+	ctx context.Context,
+	input *svcsdk.AddTagsToCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.AddTagsToCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTags(c.tags, input.Tags); err != nil {
+		return nil, err
+	}
+	for _, t := range input.Tags {
+		i := slices.IndexFunc(c.tags, func(e svcsdktypes.Tag) bool {
+			return aws.ToString(e.Key) == aws.ToString(t.Key)
+		})
+		if i >= 0 {
+			c.tags[i] = t
+		} else {
+			c.tags = append(c.tags, t)
+		}
+	}
+	return &svcsdk.AddTagsToCertificateOutput{}, nil
+}
+
+// RemoveTagsFromCertificate removes tags from a certificate. Tags given
+// with a value are only removed if their value matches.
+func (s *Server) RemoveTagsFromCertificate(
+	ctx context.Context,
+	input *svcsdk.RemoveTagsFromCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.RemoveTagsFromCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range input.Tags {
+		c.tags = slices.DeleteFunc(c.tags, func(e svcsdktypes.Tag) bool {
+			return aws.ToString(e.Key) == aws.ToString(t.Key) &&
+				(t.Value == nil || aws.ToString(e.Value) == *t.Value)
+		})
+	}
+	return &svcsdk.RemoveTagsFromCertificateOutput{}, nil
+}
+
+// ListTagsForCertificate returns the tags of a certificate.
+func (s *Server) ListTagsForCertificate(
+	ctx context.Context,
+	input *svcsdk.ListTagsForCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.ListTagsForCertificateOutput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(aws.ToString(input.CertificateArn))
+	if err != nil {
+		return nil, err
+	}
+	return &svcsdk.ListTagsForCertificateOutput{
+		Tags: append([]svcsdktypes.Tag{}, c.tags...),
+	}, nil
+}
+
+// domainValidations returns the pending DNS validations of the supplied
+// domains, using the validation domains of options where given.
+func domainValidations(
+	domains []string,
+	options []svcsdktypes.DomainValidationOption,
+) []svcsdktypes.DomainValidation {
+	dvs := make([]svcsdktypes.DomainValidation, 0, len(domains))
+	for _, domain := range domains {
+		validationDomain := domain
+		for _, o := range options {
+			if aws.ToString(o.DomainName) == domain && o.ValidationDomain != nil {
+				validationDomain = *o.ValidationDomain
+			}
+		}
+		sum := sha256.Sum256([]byte(domain))
+		name := strings.TrimPrefix(domain, "*.")
+		dvs = append(dvs, svcsdktypes.DomainValidation{
+			DomainName:       aws.String(domain),
+			ValidationDomain: aws.String(validationDomain),
+			ValidationMethod: svcsdktypes.ValidationMethodDns,
+			ValidationStatus: svcsdktypes.DomainStatusPendingValidation,
+			ResourceRecord: &svcsdktypes.ResourceRecord{
+				Name:  aws.String(fmt.Sprintf("_%s.%s.", hex.EncodeToString(sum[:16]), name)),
+				Type:  svcsdktypes.RecordTypeCname,
+				Value: aws.String(fmt.Sprintf("_%s.acm-validations.aws.", hex.EncodeToString(sum[16:]))),
+			},
+		})
+	}
+	return dvs
+}
+
+// checkTags returns the error ACM returns if adding the supplied tags to a
+// certificate that has the existing tags is not allowed.
+func checkTags(existing []svcsdktypes.Tag, tags []svcsdktypes.Tag) error {
+	keys := map[string]bool{}
+	for _, t := range existing {
+		keys[aws.ToString(t.Key)] = true
+	}
+	for _, t := range tags {
+		key := aws.ToString(t.Key)
+		if key == "" || strings.HasPrefix(strings.ToLower(key), "aws:") {
+			return &svcsdktypes.InvalidTagException{
+				Message: aws.String(fmt.Sprintf("Tag key %q is not allowed", key)),
+			}
+		}
+		keys[key] = true
+	}
+	if len(keys) > maxTags {
+		return &svcsdktypes.TooManyTagsException{
+			Message: aws.String(fmt.Sprintf("A certificate can have at most %d tags", maxTags)),
+		}
+	}
+	return nil
+}
+
+// validationError returns a ValidationException with the supplied message.
+func validationError(msg string) error {
+	return &svcsdktypes.ValidationException{Message: aws.String(msg)}
+}
@@ -0,0 +1,173 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fakeacm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	// targetPrefix prefixes the operation name in the X-Amz-Target header
+	// of ACM requests.
+	targetPrefix = "CertificateManager."
+	contentType  = "application/x-amz-json-1.1"
+)
+
+// operation handles the JSON encoded input of an ACM operation and returns
+// its output.
+type operation func(ctx context.Context, body []byte) (any, error)
+
+// handle returns an operation that decodes its input into the input type
+// of the supplied Server method.
+func handle[I any, O any](
+	fn func(context.Context, *I, ...func(*svcsdk.Options)) (*O, error),
+) operation {
+	return func(ctx context.Context, body []byte) (any, error) {
+		input := new(I)
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, input); err != nil {
+				return nil, &smithy.GenericAPIError{
+					Code:    "SerializationException",
+					Message: err.Error(),
+				}
+			}
+		}
+		return fn(ctx, input)
+	}
+}
+
+// operations returns the ACM operations the Server implements, by name.
+func (s *Server) operations() map[string]operation {
+	return map[string]operation{
+		"AddTagsToCertificate":      handle(s.AddTagsToCertificate),
+		"DeleteCertificate":         handle(s.DeleteCertificate),
+		"DescribeCertificate":       handle(s.DescribeCertificate),
+		"ExportCertificate":         handle(s.ExportCertificate),
+		"ImportCertificate":         handle(s.ImportCertificate),
+		"ListTagsForCertificate":    handle(s.ListTagsForCertificate),
+		"RemoveTagsFromCertificate": handle(s.RemoveTagsFromCertificate),
+		"RequestCertificate":        handle(s.RequestCertificate),
+		"UpdateCertificateOptions":  handle(s.UpdateCertificateOptions),
+	}
+}
+
+// ServeHTTP serves the ACM JSON 1.1 protocol, and the STS
+// GetCallerIdentity action. Requests are not authenticated.
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "InvalidAction", "Only POST requests are supported")
+		return
+	}
+	if isIdentityRequest(req) {
+		s.serveIdentity(w, req)
+		return
+	}
+	name := strings.TrimPrefix(req.Header.Get("X-Amz-Target"), targetPrefix)
+	op, found := s.operations()[name]
+	if !found {
+		writeError(w, http.StatusBadRequest, "UnknownOperationException",
+			fmt.Sprintf("Operation %q is not supported", name))
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "SerializationException", err.Error())
+		return
+	}
+	output, err := op(req.Context(), body)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			writeError(w, http.StatusBadRequest, apiErr.ErrorCode(), apiErr.ErrorMessage())
+		} else {
+			writeError(w, http.StatusInternalServerError, "InternalFailure", err.Error())
+		}
+		return
+	}
+	data, err := json.Marshal(encode(reflect.ValueOf(output)))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "InternalFailure", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(data)
+}
+
+// writeError writes an error response the way ACM does.
+func writeError(w http.ResponseWriter, status int, code string, msg string) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"__type":  code,
+		"message": msg,
+	})
+}
+
+// encode returns the supplied SDK value in the shape the JSON 1.1 protocol
+// expects once marshalled: nil values and the SDK's own bookkeeping fields
+// are left out, and timestamps are seconds since the epoch.
+func encode(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encode(v.Elem())
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return float64(t.UnixNano()) / float64(time.Second)
+		}
+		m := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() || f.Name == "ResultMetadata" {
+				continue
+			}
+			if e := encode(v.Field(i)); e != nil {
+				m[f.Name] = e
+			}
+		}
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Bytes()
+		}
+		s := make([]any, v.Len())
+		for i := range s {
+			s[i] = encode(v.Index(i))
+		}
+		return s
+	case reflect.String:
+		if v.Len() == 0 {
+			return nil
+		}
+		return v.String()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fakeacm implements an in-memory fake of the ACM API, so that the
+// resource manager can be exercised without AWS credentials.
+//
+// A Server implements the ACM operations the controller uses with the same
+// signatures as the ACM client, and serves them over the JSON 1.1 protocol
+// ACM speaks. Client and Config return an ACM client, or an aws.Config to
+// build one from, whose requests are handled in-process by the Server.
+package fakeacm
+
+import (
+	"crypto"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+const (
+	defaultAccountID = "111122223333"
+	defaultRegion    = "us-west-2"
+	defaultValidity  = 395 * 24 * time.Hour
+	// validationTimeout is how long a requested certificate stays
+	// PENDING_VALIDATION before it times out, as in ACM.
+	validationTimeout = 72 * time.Hour
+	// endpoint is the base endpoint of the clients returned by Client and
+	// Config. Requests never leave the process.
+	endpoint = "https://acm.fakeacm.local"
+)
+
+// Options configures a Server.
+type Options struct {
+	// AccountID and Region are used in the ARNs of certificates.
+	AccountID string
+	Region    string
+	// ValidationDelay is how long requested public certificates stay
+	// PENDING_VALIDATION before they are issued. A negative delay keeps
+	// them pending until Issue or Fail is called, or until they time out
+	// like in ACM, 72 hours after they were requested.
+	ValidationDelay time.Duration
+	// FailDomains are the domains, including their subdomains, whose
+	// public certificates fail validation once the validation delay has
+	// passed instead of being issued, the way ACM fails requests for
+	// domains that need additional verification.
+	FailDomains []string
+	// Validity is how long issued certificates are valid for.
+	Validity time.Duration
+	// RenewBefore is how long before they expire requested certificates are
+	// renewed. Renewals of public certificates are pending for the
+	// validation delay. Zero disables renewals.
+	RenewBefore time.Duration
+	// Now returns the current time. It defaults to time.Now and can be
+	// replaced to control validation and expiry in tests.
+	Now func() time.Time
+}
+
+// Server is an in-memory ACM. It is safe for concurrent use.
+type Server struct {
+	opts   Options
+	issuer *issuer
+
+	mu sync.Mutex
+	// certs holds certificates by ARN.
+	certs map[string]*certificate
+	// idempotencyTokens maps the idempotency tokens of RequestCertificate
+	// calls to the ARN of the certificate they requested.
+	idempotencyTokens map[string]string
+}
+
+// certificate is a certificate held by a Server.
+type certificate struct {
+	detail svcsdktypes.CertificateDetail
+	tags   []svcsdktypes.Tag
+	// keyAlgorithm is the key algorithm the certificate was requested
+	// with, e.g. RSA_2048.
+	keyAlgorithm svcsdktypes.KeyAlgorithm
+	// certPEM and chainPEM are the PEM encoded certificate and chain, and
+	// key its private key, once the certificate is issued or imported.
+	certPEM  []byte
+	chainPEM []byte
+	key      crypto.Signer
+	// pendingUntil is when a certificate pending validation is issued, or
+	// a pending renewal completes.
+	pendingUntil time.Time
+}
+
+// New returns an empty Server.
+func New(opts Options) (*Server, error) {
+	if opts.AccountID == "" {
+		opts.AccountID = defaultAccountID
+	}
+	if opts.Region == "" {
+		opts.Region = defaultRegion
+	}
+	if opts.Validity == 0 {
+		opts.Validity = defaultValidity
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	iss, err := newIssuer(opts.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		opts:              opts,
+		issuer:            iss,
+		certs:             map[string]*certificate{},
+		idempotencyTokens: map[string]string{},
+	}, nil
+}
+
+// Config returns an aws.Config whose ACM clients send their requests to
+// the Server.
+func (s *Server) Config() aws.Config {
+	return aws.Config{
+		Region:       s.opts.Region,
+		Credentials:  aws.AnonymousCredentials{},
+		HTTPClient:   s,
+		BaseEndpoint: aws.String(endpoint),
+	}
+}
+
+// Client returns an ACM client that sends its requests to the Server.
+func (s *Server) Client() *svcsdk.Client {
+	return svcsdk.NewFromConfig(s.Config())
+}
+
+// Do handles the supplied ACM request in-process, so that a Server can be
+// used as the HTTP client of an ACM client.
+func (s *Server) Do(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// CACertificatePEM returns the PEM encoded certificate of the CA that
+// issues the certificates requested from the Server.
+func (s *Server) CACertificatePEM() []byte {
+	return s.issuer.certPEM
+}
+
+// Issue issues the supplied certificate, which must be pending validation.
+func (s *Server) Issue(arn string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(arn)
+	if err != nil {
+		return err
+	}
+	if c.detail.Status != svcsdktypes.CertificateStatusPendingValidation {
+		return invalidState(arn, c.detail.Status)
+	}
+	return s.issue(c)
+}
+
+// Fail fails the validation of the supplied certificate, which must be
+// pending validation.
+func (s *Server) Fail(arn string, reason svcsdktypes.FailureReason) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(arn)
+	if err != nil {
+		return err
+	}
+	if c.detail.Status != svcsdktypes.CertificateStatusPendingValidation {
+		return invalidState(arn, c.detail.Status)
+	}
+	s.fail(c, reason)
+	return nil
+}
+
+// Renew issues a new certificate in place of the supplied requested
+// certificate, which must be issued, the way a managed renewal does.
+func (s *Server) Renew(arn string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(arn)
+	if err != nil {
+		return err
+	}
+	if c.detail.Status != svcsdktypes.CertificateStatusIssued {
+		return invalidState(arn, c.detail.Status)
+	}
+	if c.detail.Type == svcsdktypes.CertificateTypeImported {
+		return validationError("Imported certificates cannot be renewed")
+	}
+	now := s.opts.Now()
+	if err := s.issue(c); err != nil {
+		return err
+	}
+	c.detail.RenewalSummary = &svcsdktypes.RenewalSummary{
+		RenewalStatus:           svcsdktypes.RenewalStatusSuccess,
+		DomainValidationOptions: slices.Clone(c.detail.DomainValidationOptions),
+		UpdatedAt:               aws.Time(now),
+	}
+	return nil
+}
+
+// SetInUseBy sets the ARNs of the AWS resources that use the supplied
+// certificate, which prevents it from being deleted.
+func (s *Server) SetInUseBy(arn string, inUseBy ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, err := s.get(arn)
+	if err != nil {
+		return err
+	}
+	c.detail.InUseBy = append([]string{}, inUseBy...)
+	return nil
+}
+
+// get returns the certificate with the supplied ARN, after moving it to
+// the state it has reached by now. s.mu must be held.
+func (s *Server) get(arn string) (*certificate, error) {
+	c, found := s.certs[arn]
+	if !found {
+		return nil, &svcsdktypes.ResourceNotFoundException{
+			Message: aws.String(fmt.Sprintf("Could not find certificate %s.", arn)),
+		}
+	}
+	if err := s.advance(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// advance issues or fails the supplied certificate once its validation
+// delay has passed, times it out if it is still pending validation after
+// validationTimeout, renews it once it is within RenewBefore of expiring,
+// and expires it once it is no longer valid. s.mu must be held.
+func (s *Server) advance(c *certificate) error {
+	now := s.opts.Now()
+	validated := s.opts.ValidationDelay >= 0 && !now.Before(c.pendingUntil)
+	if c.detail.Status == svcsdktypes.CertificateStatusPendingValidation && validated {
+		if s.failsValidation(c) {
+			s.fail(c, svcsdktypes.FailureReasonAdditionalVerificationRequired)
+			return nil
+		}
+		if err := s.issue(c); err != nil {
+			return err
+		}
+	}
+	if c.detail.Status == svcsdktypes.CertificateStatusPendingValidation &&
+		c.detail.CreatedAt != nil && !now.Before(c.detail.CreatedAt.Add(validationTimeout)) {
+		c.detail.Status = svcsdktypes.CertificateStatusValidationTimedOut
+	}
+	if c.detail.Status == svcsdktypes.CertificateStatusIssued && s.renews(c, now) {
+		if err := s.renew(c, now); err != nil {
+			return err
+		}
+	}
+	if c.detail.Status == svcsdktypes.CertificateStatusIssued &&
+		c.detail.NotAfter != nil && now.After(*c.detail.NotAfter) {
+		c.detail.Status = svcsdktypes.CertificateStatusExpired
+	}
+	return nil
+}
+
+// failsValidation returns true if the domain of the supplied certificate is
+// one of FailDomains or a subdomain of one.
+func (s *Server) failsValidation(c *certificate) bool {
+	domain := strings.TrimPrefix(aws.ToString(c.detail.DomainName), "*.")
+	for _, d := range s.opts.FailDomains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// renews returns true if the supplied issued certificate is due for
+// renewal.
+func (s *Server) renews(c *certificate, now time.Time) bool {
+	return s.opts.RenewBefore > 0 &&
+		c.detail.Type != svcsdktypes.CertificateTypeImported &&
+		c.detail.NotAfter != nil &&
+		!now.Before(c.detail.NotAfter.Add(-s.opts.RenewBefore))
+}
+
+// renew moves the renewal of the supplied certificate along: public
+// certificates first have a renewal pending validation for the validation
+// delay, then a new certificate is issued. s.mu must be held.
+func (s *Server) renew(c *certificate, now time.Time) error {
+	summary := c.detail.RenewalSummary
+	pending := summary != nil && summary.RenewalStatus == svcsdktypes.RenewalStatusPendingAutoRenewal
+	if c.detail.Type == svcsdktypes.CertificateTypeAmazonIssued && s.opts.ValidationDelay != 0 {
+		if !pending {
+			c.pendingUntil = now.Add(s.opts.ValidationDelay)
+			c.detail.RenewalSummary = &svcsdktypes.RenewalSummary{
+				RenewalStatus:           svcsdktypes.RenewalStatusPendingAutoRenewal,
+				DomainValidationOptions: slices.Clone(c.detail.DomainValidationOptions),
+				UpdatedAt:               aws.Time(now),
+			}
+			return nil
+		}
+		if s.opts.ValidationDelay < 0 || now.Before(c.pendingUntil) {
+			return nil
+		}
+	}
+	if err := s.issue(c); err != nil {
+		return err
+	}
+	c.detail.RenewalSummary = &svcsdktypes.RenewalSummary{
+		RenewalStatus:           svcsdktypes.RenewalStatusSuccess,
+		DomainValidationOptions: slices.Clone(c.detail.DomainValidationOptions),
+		UpdatedAt:               aws.Time(now),
+	}
+	return nil
+}
+
+// fail fails the validation of the supplied certificate. s.mu must be held.
+func (s *Server) fail(c *certificate, reason svcsdktypes.FailureReason) {
+	c.detail.Status = svcsdktypes.CertificateStatusFailed
+	c.detail.FailureReason = reason
+	for i := range c.detail.DomainValidationOptions {
+		c.detail.DomainValidationOptions[i].ValidationStatus = svcsdktypes.DomainStatusFailed
+	}
+}
+
+// issue signs a certificate for the domains of the supplied certificate
+// and marks it as issued. s.mu must be held.
+func (s *Server) issue(c *certificate) error {
+	now := s.opts.Now()
+	leaf, key, err := s.issuer.issue(
+		aws.ToString(c.detail.DomainName),
+		c.detail.SubjectAlternativeNames,
+		c.keyAlgorithm,
+		now, now.Add(s.opts.Validity),
+	)
+	if err != nil {
+		return err
+	}
+	c.certPEM = encodeCertificate(leaf)
+	c.chainPEM = s.issuer.certPEM
+	c.key = key
+	c.detail.Status = svcsdktypes.CertificateStatusIssued
+	c.detail.IssuedAt = aws.Time(now)
+	setCertificateDetails(&c.detail, leaf)
+	c.detail.RenewalEligibility = svcsdktypes.RenewalEligibilityEligible
+	for i := range c.detail.DomainValidationOptions {
+		c.detail.DomainValidationOptions[i].ValidationStatus = svcsdktypes.DomainStatusSuccess
+	}
+	return nil
+}
+
+// newARN returns a new certificate ARN.
+func (s *Server) newARN() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	h := hex.EncodeToString(id)
+	return fmt.Sprintf(
+		"arn:aws:acm:%s:%s:certificate/%s-%s-%s-%s-%s",
+		s.opts.Region, s.opts.AccountID, h[0:8], h[8:12], h[12:16], h[16:20], h[20:32],
+	)
+}
+
+// invalidState returns the error ACM returns for operations on a
+// certificate in the wrong state.
+func invalidState(arn string, status svcsdktypes.CertificateStatus) error {
+	return &svcsdktypes.InvalidStateException{
+		Message: aws.String(fmt.Sprintf("Certificate %s is in state %s.", arn, status)),
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	flag "github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=acm.services.k8s.aws,resources=certificateapprovals,verbs=list;patch;watch
+
+const (
+	flagCertificateApprovalWebhook = "certificate-approval-webhook"
+
+	// ConditionTypeAwaitingApproval is the type of the condition that is True
+	// while a new Certificate waits for a CertificateApproval.
+	ConditionTypeAwaitingApproval ackv1alpha1.ConditionType = "AwaitingApproval"
+
+	// ApprovalDecisionApproved is the decision of a CertificateApproval that
+	// lets the controller request or import the certificate.
+	ApprovalDecisionApproved = "Approved"
+	// ApprovalDecisionDenied is the decision of a CertificateApproval that
+	// stops the controller from requesting or importing the certificate.
+	ApprovalDecisionDenied = "Denied"
+
+	// AnnotationApprovedRequest is the annotation the controller sets on a
+	// CertificateApproval when it first acts on it, identifying the request
+	// that was approved. An approval only approves that request, so that a
+	// replacement with other domains, CA or key algorithm, or a renewed
+	// import source with other domains, needs a new approval.
+	AnnotationApprovedRequest = "acm.services.k8s.aws/approved-request"
+)
+
+var (
+	// approvalWebhook is true when the --certificate-approval-webhook flag
+	// says that the mutating webhook recording the approver of
+	// CertificateApprovals is registered and rejects approvals when it
+	// cannot be called.
+	approvalWebhook bool
+
+	// errApprovalWebhookDisabled is returned for a Certificate that needs
+	// approval while approvalWebhook is false. Without the webhook, anyone
+	// who may create a CertificateApproval can set its approver.
+	errApprovalWebhookDisabled = fmt.Errorf(
+		"a CertificatePolicy requires approval, but CertificateApprovals are not trusted "+
+			"without the --%s flag", flagCertificateApprovalWebhook,
+	)
+)
+
+func init() {
+	flag.BoolVar(
+		&approvalWebhook, flagCertificateApprovalWebhook, false,
+		"Trust the approver of CertificateApprovals. Only set this when the "+
+			"mutating webhook of the controller is registered for "+
+			"CertificateApprovals with failurePolicy Fail. Certificates that "+
+			"a CertificatePolicy requires approval for are not requested or "+
+			"imported without it.",
+	)
+}
+
+// awaitApproval checks whether the CertificatePolicies applying to the
+// namespace of the supplied resource require approval of the supplied
+// request and, if so, whether a CertificateApproval decided on it. The
+// returned resource records the decision in Status.Approval and the
+// AwaitingApproval condition, including when an error is returned: a
+// requeue error while no decision was made, or a Terminal error if the
+// certificate was denied or approvals are not trusted.
+func (rm *resourceManager) awaitApproval(
+	ctx context.Context,
+	r *resource,
+	req policyRequest,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.awaitApproval")
+	defer func() { exit(err) }()
+
+	required, err := rm.certificateApprovalRequired(ctx, r.ko.Namespace, req)
+	if err != nil || !required {
+		return r, err
+	}
+	if !approvalWebhook {
+		return r, ackerr.NewTerminalError(errApprovalWebhookDisabled)
+	}
+	approval, err := rm.findCertificateApproval(ctx, r.ko, req)
+	if err != nil {
+		return r, err
+	}
+	ko := r.ko.DeepCopy()
+	if approval == nil {
+		setApprovalCondition(ko, corev1.ConditionTrue, fmt.Sprintf(
+			"waiting for a CertificateApproval of Certificate %s in namespace %s", ko.Name, ko.Namespace,
+		))
+		return &resource{ko}, ackrequeue.NeededAfter(errors.New("certificate is awaiting approval"), requeuePending)
+	}
+	if err := rm.bindCertificateApproval(ctx, ko, approval, req); err != nil {
+		return r, err
+	}
+	ko.Status.Approval = &svcapitypes.CertificateApprovalRecord{
+		Name:     approval.Name,
+		Decision: approval.Spec.Decision,
+		Approver: approval.Spec.Approver,
+		Reason:   approval.Spec.Reason,
+	}
+	message := fmt.Sprintf("%s by %s in CertificateApproval %s", approval.Spec.Decision, approval.Spec.Approver, approval.Name)
+	if approval.Spec.Reason != "" {
+		message += ": " + approval.Spec.Reason
+	}
+	setApprovalCondition(ko, corev1.ConditionFalse, message)
+	if approval.Spec.Decision == ApprovalDecisionDenied {
+		rm.recordEvent(ko, corev1.EventTypeWarning, ApprovalDecisionDenied, "Certificate %s", message)
+		return &resource{ko}, ackerr.NewTerminalError(fmt.Errorf(
+			"certificate was denied by %s in CertificateApproval %s", approval.Spec.Approver, approval.Name,
+		))
+	}
+	rm.recordEvent(ko, corev1.EventTypeNormal, ApprovalDecisionApproved, "Certificate %s", message)
+	rlog.Info("certificate approved", "approval", approval.Name, "approver", approval.Spec.Approver)
+	return &resource{ko}, nil
+}
+
+// awaitRequestApproval runs awaitApproval for the supplied request of the
+// certificate of the supplied object, and records the decision in its
+// status. It is used before requesting or importing a certificate other
+// than the first one of a Certificate.
+func (rm *resourceManager) awaitRequestApproval(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	req policyRequest,
+) error {
+	approved, err := rm.awaitApproval(ctx, &resource{ko}, req)
+	ko.Status.Approval = approved.ko.Status.Approval
+	ko.Status.Conditions = approved.ko.Status.Conditions
+	return err
+}
+
+// findCertificateApproval returns the CertificateApproval deciding on the
+// supplied request of the supplied Certificate, or nil if none exists. A
+// denial takes precedence over an approval. Approvals without an approver,
+// which were not admitted by the webhook, approvals bound to an earlier
+// Certificate with the same name and approvals bound to another request are
+// ignored.
+func (rm *resourceManager) findCertificateApproval(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	req policyRequest,
+) (*svcapitypes.CertificateApproval, error) {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	approvals := &svcapitypes.CertificateApprovalList{}
+	if err := kc.List(ctx, approvals, client.InNamespace(ko.Namespace)); err != nil {
+		return nil, fmt.Errorf("listing CertificateApprovals in namespace %s: %w", ko.Namespace, err)
+	}
+	var found *svcapitypes.CertificateApproval
+	for i := range approvals.Items {
+		approval := &approvals.Items[i]
+		if approval.Spec.CertificateName != ko.Name || approval.Spec.Approver == "" {
+			continue
+		}
+		if owner := metav1.GetControllerOf(approval); owner != nil &&
+			(owner.UID != ko.UID || approval.Annotations[AnnotationApprovedRequest] != approvedRequest(req)) {
+			continue
+		}
+		if approval.Spec.Decision == ApprovalDecisionDenied {
+			return approval, nil
+		}
+		if found == nil && approval.Spec.Decision == ApprovalDecisionApproved {
+			found = approval
+		}
+	}
+	return found, nil
+}
+
+// bindCertificateApproval makes the supplied Certificate the controller
+// owner of the supplied CertificateApproval and records the supplied
+// request in its AnnotationApprovedRequest annotation, so that the approval
+// is deleted with the Certificate and cannot approve a later Certificate
+// with the same name or another request.
+func (rm *resourceManager) bindCertificateApproval(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	approval *svcapitypes.CertificateApproval,
+	req policyRequest,
+) error {
+	if metav1.GetControllerOf(approval) != nil {
+		return nil
+	}
+	if planDryRun(ctx, "set the owner of CertificateApproval %s/%s to the Certificate", approval.Namespace, approval.Name) {
+		return nil
+	}
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return err
+	}
+	patch := client.MergeFromWithOptions(approval.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if approval.Annotations == nil {
+		approval.Annotations = map[string]string{}
+	}
+	approval.Annotations[AnnotationApprovedRequest] = approvedRequest(req)
+	approval.OwnerReferences = append(approval.OwnerReferences, metav1.OwnerReference{
+		APIVersion: svcapitypes.GroupVersion.String(),
+		Kind:       GroupKind.Kind,
+		Name:       ko.Name,
+		UID:        ko.UID,
+		Controller: aws.Bool(true),
+	})
+	if err := kc.Patch(ctx, approval, patch); err != nil {
+		return fmt.Errorf("binding CertificateApproval %s/%s: %w", approval.Namespace, approval.Name, err)
+	}
+	return nil
+}
+
+// approvedRequest returns the value of the AnnotationApprovedRequest
+// annotation for the supplied request.
+func approvedRequest(req policyRequest) string {
+	domains := slices.Clone(req.domains)
+	slices.Sort(domains)
+	sum := sha256.Sum256([]byte(fmt.Sprintf(
+		"%t/%s/%s/%s", req.imported, req.certificateAuthorityARN, req.keyAlgorithm, strings.Join(domains, ","),
+	)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// setApprovalCondition sets the AwaitingApproval condition of the supplied
+// object to the supplied status and message.
+func setApprovalCondition(
+	ko *svcapitypes.Certificate,
+	status corev1.ConditionStatus,
+	message string,
+) {
+	var condition *ackv1alpha1.Condition
+	for _, c := range ko.Status.Conditions {
+		if c.Type == ConditionTypeAwaitingApproval {
+			condition = c
+		}
+	}
+	if condition == nil {
+		condition = &ackv1alpha1.Condition{Type: ConditionTypeAwaitingApproval}
+		ko.Status.Conditions = append(ko.Status.Conditions, condition)
+	}
+	if condition.Status != status {
+		condition.Status = status
+		condition.LastTransitionTime = &metav1.Time{Time: time.Now()}
+	}
+	condition.Message = aws.String(message)
+}
@@ -0,0 +1,246 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// approvalPolicyObjects returns the namespace of the test Certificates and
+// a CertificatePolicy requiring approval in it.
+func approvalPolicyObjects() []client.Object {
+	return []client.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+		&svcapitypes.CertificatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "approval"},
+			Spec:       svcapitypes.CertificatePolicySpec{Namespaces: []string{testNamespace}, RequireApproval: true},
+		},
+	}
+}
+
+// trustApprovals sets approvalWebhook until the test ends.
+func trustApprovals(t *testing.T, trusted bool) {
+	t.Helper()
+	previous := approvalWebhook
+	approvalWebhook = trusted
+	t.Cleanup(func() { approvalWebhook = previous })
+}
+
+// testApproval returns a CertificateApproval of the test Certificate.
+func testApproval(name string, decision string, approver string) *svcapitypes.CertificateApproval {
+	return &svcapitypes.CertificateApproval{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: svcapitypes.CertificateApprovalSpec{
+			CertificateName: "test",
+			Decision:        decision,
+			Approver:        approver,
+		},
+	}
+}
+
+// boundApproval returns the supplied approval bound to the Certificate with
+// the supplied UID for the supplied request.
+func boundApproval(
+	approval *svcapitypes.CertificateApproval,
+	uid types.UID,
+	req policyRequest,
+) *svcapitypes.CertificateApproval {
+	approval.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: svcapitypes.GroupVersion.String(),
+		Kind:       GroupKind.Kind,
+		Name:       "test",
+		UID:        uid,
+		Controller: aws.Bool(true),
+	}}
+	approval.Annotations = map[string]string{AnnotationApprovedRequest: approvedRequest(req)}
+	return approval
+}
+
+func TestAwaitApproval(t *testing.T) {
+	req := policyRequest{domains: []string{"www.example.com"}, keyAlgorithm: "RSA_2048"}
+	otherReq := policyRequest{domains: []string{"api.example.com"}, keyAlgorithm: "RSA_2048"}
+	tests := []struct {
+		name      string
+		approvals []client.Object
+		// untrusted runs the test without the approval webhook flag.
+		untrusted bool
+		// wantApproval is the name of the approval acted on, or empty if the
+		// Certificate awaits approval.
+		wantApproval string
+		wantTerminal bool
+	}{
+		{
+			name: "no approval",
+		},
+		{
+			name:         "unbound approval",
+			approvals:    []client.Object{testApproval("ok", ApprovalDecisionApproved, "alice")},
+			wantApproval: "ok",
+		},
+		{
+			name:      "approval without approver",
+			approvals: []client.Object{testApproval("forged", ApprovalDecisionApproved, "")},
+		},
+		{
+			name: "approval bound to the request",
+			approvals: []client.Object{
+				boundApproval(testApproval("ok", ApprovalDecisionApproved, "alice"), "test-uid", req),
+			},
+			wantApproval: "ok",
+		},
+		{
+			name: "approval bound to another request",
+			approvals: []client.Object{
+				boundApproval(testApproval("earlier", ApprovalDecisionApproved, "alice"), "test-uid", otherReq),
+			},
+		},
+		{
+			name: "approval bound to an earlier Certificate",
+			approvals: []client.Object{
+				boundApproval(testApproval("earlier", ApprovalDecisionApproved, "alice"), "earlier-uid", req),
+			},
+		},
+		{
+			name: "new approval after an approval of another request",
+			approvals: []client.Object{
+				boundApproval(testApproval("earlier", ApprovalDecisionApproved, "alice"), "test-uid", otherReq),
+				testApproval("ok", ApprovalDecisionApproved, "bob"),
+			},
+			wantApproval: "ok",
+		},
+		{
+			name: "denial takes precedence",
+			approvals: []client.Object{
+				testApproval("ok", ApprovalDecisionApproved, "alice"),
+				testApproval("no", ApprovalDecisionDenied, "bob"),
+			},
+			wantApproval: "no",
+			wantTerminal: true,
+		},
+		{
+			name:         "approvals are not trusted without the webhook",
+			approvals:    []client.Object{testApproval("forged", ApprovalDecisionApproved, "alice")},
+			untrusted:    true,
+			wantTerminal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trustApprovals(t, !tt.untrusted)
+			rm, _, _ := newTestResourceManager(t)
+			kc := newFakeKubeClient(t, append(approvalPolicyObjects(), tt.approvals...)...)
+			bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+
+			updated, err := rm.awaitApproval(context.Background(), newTestCertificate(svcapitypes.CertificateSpec{}), req)
+			if isTerminal(err) != tt.wantTerminal {
+				t.Fatalf("awaitApproval error = %v, want terminal %v", err, tt.wantTerminal)
+			}
+			if tt.untrusted {
+				if !errors.Is(err, errApprovalWebhookDisabled) {
+					t.Errorf("awaitApproval error = %v, want %v", err, errApprovalWebhookDisabled)
+				}
+				return
+			}
+			if tt.wantApproval == "" {
+				if !isRequeue(err) {
+					t.Fatalf("awaitApproval error = %v, want a requeue", err)
+				}
+				if updated.ko.Status.Approval != nil {
+					t.Errorf("Status.Approval = %+v, want none", updated.ko.Status.Approval)
+				}
+				return
+			}
+			if !tt.wantTerminal && err != nil {
+				t.Fatalf("awaitApproval: %v", err)
+			}
+			if got := updated.ko.Status.Approval; got == nil || got.Name != tt.wantApproval {
+				t.Fatalf("Status.Approval = %+v, want %s", got, tt.wantApproval)
+			}
+			approval := &svcapitypes.CertificateApproval{}
+			if err := kc.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: tt.wantApproval}, approval); err != nil {
+				t.Fatal(err)
+			}
+			if owner := metav1.GetControllerOf(approval); owner == nil || owner.UID != "test-uid" {
+				t.Errorf("owner of the approval = %+v, want the Certificate", owner)
+			}
+			if got := approval.Annotations[AnnotationApprovedRequest]; got != approvedRequest(req) {
+				t.Errorf("annotation %s = %q, want %q", AnnotationApprovedRequest, got, approvedRequest(req))
+			}
+		})
+	}
+}
+
+func TestReplacementAwaitsApproval(t *testing.T) {
+	trustApprovals(t, true)
+	rm, _, _ := newTestResourceManager(t)
+	spec := svcapitypes.CertificateSpec{
+		DomainName:        aws.String("www.example.com"),
+		ReplacementPolicy: ptr(svcapitypes.ReplacementPolicyReplace),
+	}
+	latest := createCertificate(t, rm, spec)
+	latest.ko.UID = "test-uid"
+	// The approval of the first request does not approve the replacement.
+	kc := newFakeKubeClient(t, append(approvalPolicyObjects(),
+		boundApproval(testApproval("first", ApprovalDecisionApproved, "alice"), "test-uid", requestedPolicyRequest(latest.ko)),
+	)...)
+	bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+
+	desired := &resource{latest.ko.DeepCopy()}
+	desired.ko.Spec.DomainName = aws.String("api.example.com")
+	updated, err := rm.replaceCertificate(context.Background(), desired, latest, newResourceDelta(desired, latest))
+	if !isRequeue(err) {
+		t.Fatalf("replaceCertificate error = %v, want a requeue", err)
+	}
+	if updated.ko.Status.ReplacementCertificateARN != nil {
+		t.Errorf("replacement %s was requested without approval", *updated.ko.Status.ReplacementCertificateARN)
+	}
+	if !awaitingApproval(updated.ko) {
+		t.Errorf("condition %s is not True", ConditionTypeAwaitingApproval)
+	}
+
+	if err := kc.Create(context.Background(), testApproval("replacement", ApprovalDecisionApproved, "bob")); err != nil {
+		t.Fatal(err)
+	}
+	updated, err = rm.replaceCertificate(context.Background(), desired, latest, newResourceDelta(desired, latest))
+	if !isRequeue(err) {
+		t.Fatalf("replaceCertificate error = %v, want a requeue", err)
+	}
+	if updated.ko.Status.ReplacementCertificateARN == nil {
+		t.Fatal("no replacement was requested after approval")
+	}
+	if awaitingApproval(updated.ko) {
+		t.Errorf("condition %s is still True", ConditionTypeAwaitingApproval)
+	}
+}
+
+// awaitingApproval returns true if the AwaitingApproval condition of the
+// supplied object is True.
+func awaitingApproval(ko *svcapitypes.Certificate) bool {
+	for _, c := range ko.Status.Conditions {
+		if c.Type == ConditionTypeAwaitingApproval {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
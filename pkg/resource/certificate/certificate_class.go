@@ -0,0 +1,127 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=acm.services.k8s.aws,resources=certificateclasses,verbs=get;list;watch
+
+// applyCertificateClass returns a copy of the supplied resource with the
+// defaults of the CertificateClass named in Spec.CertificateClassName set,
+// or the resource itself if it names no class. It is called before the
+// certificate is requested or imported, so the runtime saves the defaults
+// in the spec of the Certificate and later changes to the class do not
+// affect it.
+func (rm *resourceManager) applyCertificateClass(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	if r.ko.Spec.CertificateClassName == nil || *r.ko.Spec.CertificateClassName == "" {
+		return r, nil
+	}
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	class, err := readCertificateClass(ctx, kc, *r.ko.Spec.CertificateClassName)
+	if err != nil {
+		return nil, ackrequeue.Needed(err)
+	}
+	ko := r.ko.DeepCopy()
+	mergeCertificateClass(ko, &class.Spec)
+	if r.ko.Spec.CertificateAuthorityRef == nil && ko.Spec.CertificateAuthorityRef != nil {
+		// The runtime resolved the references of the Certificate before
+		// the class was merged, so the one from the class is resolved here.
+		if _, err := rm.resolveReferenceForCertificateAuthorityARN(ctx, rm.kube().apiReader, ko); err != nil {
+			return nil, ackrequeue.Needed(err)
+		}
+	}
+	return &resource{ko}, nil
+}
+
+// readCertificateClass returns the CertificateClass with the supplied name.
+func readCertificateClass(
+	ctx context.Context,
+	kc client.Reader,
+	name string,
+) (*svcapitypes.CertificateClass, error) {
+	class := &svcapitypes.CertificateClass{}
+	if err := kc.Get(ctx, types.NamespacedName{Name: name}, class); err != nil {
+		return nil, fmt.Errorf("reading CertificateClass %s: %w", name, err)
+	}
+	return class, nil
+}
+
+// mergeCertificateClass sets the fields of the supplied Certificate that
+// are not set to the defaults of the supplied class, and adds the tags of
+// the class whose key the Certificate has no tag for. Only the requeue
+// policy and tags apply to an imported certificate.
+func mergeCertificateClass(
+	ko *svcapitypes.Certificate,
+	class *svcapitypes.CertificateClassSpec,
+) {
+	if len(class.Tags) > 0 {
+		resourceTags, keyOrder := convertToOrderedACKTags(ko.Spec.Tags)
+		classTags, classKeyOrder := convertToOrderedACKTags(class.Tags)
+		ko.Spec.Tags = fromACKTags(acktags.Merge(resourceTags, classTags), append(keyOrder, classKeyOrder...))
+	}
+	spec := &ko.Spec
+	if spec.RequeuePolicy == nil && class.RequeuePolicy != nil {
+		spec.RequeuePolicy = class.RequeuePolicy.DeepCopy()
+	}
+	if isImportSpec(ko) {
+		return
+	}
+	if spec.CertificateAuthorityARN == nil && spec.CertificateAuthorityRef == nil {
+		switch {
+		case class.CertificateAuthorityARN != nil:
+			spec.CertificateAuthorityARN = class.CertificateAuthorityARN
+		case class.CertificateAuthorityRef != nil:
+			spec.CertificateAuthorityRef = class.CertificateAuthorityRef.DeepCopy()
+		}
+	}
+	if spec.ExportKeyFormat == nil {
+		spec.ExportKeyFormat = class.ExportKeyFormat
+	}
+	if spec.KeyAlgorithm == nil {
+		spec.KeyAlgorithm = class.KeyAlgorithm
+	}
+	if spec.ReplacementPolicy == nil {
+		spec.ReplacementPolicy = class.ReplacementPolicy
+	}
+	if spec.ValidationRetryPolicy == nil && class.ValidationRetryPolicy != nil {
+		spec.ValidationRetryPolicy = class.ValidationRetryPolicy.DeepCopy()
+	}
+	if class.Options != nil {
+		if spec.Options == nil {
+			spec.Options = &svcapitypes.CertificateOptions{}
+		}
+		if spec.Options.CertificateTransparencyLoggingPreference == nil {
+			spec.Options.CertificateTransparencyLoggingPreference = class.Options.CertificateTransparencyLoggingPreference
+		}
+		if spec.Options.Export == nil {
+			spec.Options.Export = class.Options.Export
+		}
+	}
+}
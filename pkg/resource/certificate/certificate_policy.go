@@ -0,0 +1,307 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=acm.services.k8s.aws,resources=certificatepolicies,verbs=list;watch
+
+// policyRequest is what a Certificate asks ACM for, as checked against the
+// CertificatePolicies applying to its namespace.
+type policyRequest struct {
+	// domains are the domain names of the certificate.
+	domains []string
+	// certificateAuthorityARN is the ARN of the private CA the certificate
+	// is requested from, or empty for a public certificate.
+	certificateAuthorityARN string
+	// keyAlgorithm is the algorithm of the key pair, e.g. RSA_2048.
+	keyAlgorithm string
+	// imported is true for a certificate that is imported rather than
+	// requested.
+	imported bool
+	// certificateAuthorityUnresolved is true when the private CA is named by
+	// a reference that is not resolved yet, so that it cannot be checked.
+	certificateAuthorityUnresolved bool
+}
+
+// requestedPolicyRequest returns the policyRequest of a certificate
+// requested with the spec of the supplied Certificate.
+func requestedPolicyRequest(ko *svcapitypes.Certificate) policyRequest {
+	req := policyRequest{keyAlgorithm: string(svcsdktypes.KeyAlgorithmRsa2048)}
+	if ko.Spec.DomainName != nil {
+		req.domains = append(req.domains, *ko.Spec.DomainName)
+	}
+	for _, san := range ko.Spec.SubjectAlternativeNames {
+		if san != nil {
+			req.domains = append(req.domains, *san)
+		}
+	}
+	if ko.Spec.CertificateAuthorityARN != nil {
+		req.certificateAuthorityARN = *ko.Spec.CertificateAuthorityARN
+	} else if ko.Spec.CertificateAuthorityRef != nil {
+		req.certificateAuthorityUnresolved = true
+	}
+	if ko.Spec.KeyAlgorithm != nil {
+		req.keyAlgorithm = normalizeKeyAlgorithm(*ko.Spec.KeyAlgorithm)
+	}
+	return req
+}
+
+// importedPolicyRequest returns the policyRequest of the certificate in the
+// supplied ImportCertificate input, which has been inspected already.
+func importedPolicyRequest(input *svcsdk.ImportCertificateInput) (policyRequest, error) {
+	certs, err := parseCertificates(input.Certificate)
+	if err != nil {
+		return policyRequest{}, fmt.Errorf("certificate: %w", err)
+	}
+	if len(certs) == 0 {
+		return policyRequest{}, errors.New("certificate: no PEM encoded certificate found")
+	}
+	leaf := certs[0]
+	req := policyRequest{
+		domains:      slices.Clone(leaf.DNSNames),
+		keyAlgorithm: certificateKeyAlgorithm(leaf),
+		imported:     true,
+	}
+	if len(req.domains) == 0 && leaf.Subject.CommonName != "" {
+		req.domains = []string{leaf.Subject.CommonName}
+	}
+	return req, nil
+}
+
+// certificateKeyAlgorithm returns the ACM name of the key algorithm of the
+// supplied certificate.
+func certificateKeyAlgorithm(cert *x509.Certificate) string {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA_%d", pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return string(svcsdktypes.KeyAlgorithmEcPrime256v1)
+		case elliptic.P384():
+			return string(svcsdktypes.KeyAlgorithmEcSecp384r1)
+		case elliptic.P521():
+			return string(svcsdktypes.KeyAlgorithmEcSecp521r1)
+		}
+	}
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// checkCertificatePolicies returns a Terminal error if CertificatePolicies
+// exist and none of those applying to the supplied namespace permits the
+// supplied request.
+func (rm *resourceManager) checkCertificatePolicies(
+	ctx context.Context,
+	namespace string,
+	req policyRequest,
+) error {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return err
+	}
+	violation, err := certificatePolicyViolation(ctx, kc, namespace, req)
+	if err != nil {
+		return err
+	}
+	if violation != "" {
+		return ackerr.NewTerminalError(fmt.Errorf("certificate is not permitted: %s", violation))
+	}
+	return nil
+}
+
+// policyDecision is the outcome of checking a request against the
+// CertificatePolicies applying to a namespace.
+type policyDecision struct {
+	// violation is why the request is not permitted, or empty if it is.
+	violation string
+	// approvalRequired is true if every policy permitting the request
+	// requires approval.
+	approvalRequired bool
+}
+
+// certificatePolicyViolation returns why the supplied request is not
+// permitted in the supplied namespace, or an empty string if no
+// CertificatePolicy exists or one applying to the namespace permits it.
+func certificatePolicyViolation(
+	ctx context.Context,
+	kc client.Reader,
+	namespace string,
+	req policyRequest,
+) (string, error) {
+	decision, err := evaluateCertificatePolicies(ctx, kc, namespace, req)
+	return decision.violation, err
+}
+
+// certificateApprovalRequired returns true if the supplied request is
+// permitted in the supplied namespace only by CertificatePolicies that
+// require approval.
+func (rm *resourceManager) certificateApprovalRequired(
+	ctx context.Context,
+	namespace string,
+	req policyRequest,
+) (bool, error) {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return false, err
+	}
+	decision, err := evaluateCertificatePolicies(ctx, kc, namespace, req)
+	if err != nil {
+		return false, err
+	}
+	return decision.violation == "" && decision.approvalRequired, nil
+}
+
+// evaluateCertificatePolicies checks the supplied request against the
+// CertificatePolicies applying to the supplied namespace. A request is
+// permitted if no CertificatePolicy exists or one applying to the namespace
+// permits it.
+func evaluateCertificatePolicies(
+	ctx context.Context,
+	kc client.Reader,
+	namespace string,
+	req policyRequest,
+) (policyDecision, error) {
+	policies := &svcapitypes.CertificatePolicyList{}
+	if err := kc.List(ctx, policies); err != nil {
+		return policyDecision{}, fmt.Errorf("listing CertificatePolicies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return policyDecision{}, nil
+	}
+	var nsLabels labels.Set
+	permitted := false
+	approvalRequired := true
+	violations := []string{}
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		if policy.Spec.NamespaceSelector != nil && nsLabels == nil {
+			ns := &corev1.Namespace{}
+			if err := kc.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+				return policyDecision{}, fmt.Errorf("reading labels of namespace %q for CertificatePolicies: %w", namespace, err)
+			}
+			nsLabels = labels.Set(ns.Labels)
+		}
+		applies, err := policyApplies(&policy.Spec, namespace, nsLabels)
+		if err != nil {
+			return policyDecision{}, fmt.Errorf("CertificatePolicy %s: %w", policy.Name, err)
+		}
+		if !applies {
+			continue
+		}
+		violation := policyViolation(&policy.Spec, req)
+		if violation == "" {
+			permitted = true
+			approvalRequired = approvalRequired && policy.Spec.RequireApproval
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("CertificatePolicy %s: %s", policy.Name, violation))
+	}
+	if permitted {
+		return policyDecision{approvalRequired: approvalRequired}, nil
+	}
+	if len(violations) == 0 {
+		return policyDecision{violation: fmt.Sprintf("no CertificatePolicy applies to namespace %s", namespace)}, nil
+	}
+	return policyDecision{violation: strings.Join(violations, "; ")}, nil
+}
+
+// policyApplies returns true if the supplied policy applies to the
+// namespace with the supplied name and labels.
+func policyApplies(
+	policy *svcapitypes.CertificatePolicySpec,
+	namespace string,
+	nsLabels labels.Set,
+) (bool, error) {
+	if slices.Contains(policy.Namespaces, namespace) {
+		return true, nil
+	}
+	if policy.NamespaceSelector == nil {
+		return false, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(nsLabels), nil
+}
+
+// policyViolation returns why the supplied policy does not permit the
+// supplied request, or an empty string if it does.
+func policyViolation(
+	policy *svcapitypes.CertificatePolicySpec,
+	req policyRequest,
+) string {
+	if len(policy.DomainPatterns) > 0 {
+		for _, domain := range req.domains {
+			if !domainPermitted(policy.DomainPatterns, domain) {
+				return fmt.Sprintf("domain %s does not match any of %s", domain, strings.Join(policy.DomainPatterns, ", "))
+			}
+		}
+	}
+	if len(policy.CertificateAuthorityARNs) > 0 && !req.imported && !req.certificateAuthorityUnresolved {
+		if req.certificateAuthorityARN == "" {
+			return "public certificates are not permitted"
+		}
+		if !slices.Contains(policy.CertificateAuthorityARNs, req.certificateAuthorityARN) {
+			return fmt.Sprintf("certificate authority %s is not permitted", req.certificateAuthorityARN)
+		}
+	}
+	if len(policy.KeyAlgorithms) > 0 && !slices.ContainsFunc(policy.KeyAlgorithms, func(algorithm string) bool {
+		return normalizeKeyAlgorithm(algorithm) == req.keyAlgorithm
+	}) {
+		return fmt.Sprintf("key algorithm %s is not permitted", req.keyAlgorithm)
+	}
+	return ""
+}
+
+// domainPermitted returns true if the supplied domain name matches one of
+// the supplied CertificatePolicy domain patterns.
+func domainPermitted(patterns []string, domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+		if parent, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(domain, "."+parent) {
+				return true
+			}
+			continue
+		}
+		if domain == pattern {
+			return true
+		}
+	}
+	return false
+}
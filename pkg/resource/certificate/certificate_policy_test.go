@@ -0,0 +1,388 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestDomainPermitted(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		domain   string
+		want     bool
+	}{
+		{name: "exact name", patterns: []string{"www.example.com"}, domain: "www.example.com", want: true},
+		{name: "other name", patterns: []string{"www.example.com"}, domain: "api.example.com", want: false},
+		{name: "exact name does not permit subdomains", patterns: []string{"example.com"}, domain: "www.example.com", want: false},
+		{name: "exact name does not permit wildcard", patterns: []string{"example.com"}, domain: "*.example.com", want: false},
+		{name: "wildcard permits subdomain", patterns: []string{"*.example.com"}, domain: "www.example.com", want: true},
+		{name: "wildcard permits nested subdomains", patterns: []string{"*.example.com"}, domain: "a.b.c.example.com", want: true},
+		{name: "wildcard permits wildcard name", patterns: []string{"*.example.com"}, domain: "*.example.com", want: true},
+		{name: "wildcard permits nested wildcard name", patterns: []string{"*.example.com"}, domain: "*.www.example.com", want: true},
+		{name: "wildcard does not permit apex", patterns: []string{"*.example.com"}, domain: "example.com", want: false},
+		{name: "wildcard does not permit suffix match", patterns: []string{"*.example.com"}, domain: "badexample.com", want: false},
+		{name: "wildcard does not permit parent", patterns: []string{"*.www.example.com"}, domain: "api.example.com", want: false},
+		{name: "nested wildcard permits deeper names", patterns: []string{"*.www.example.com"}, domain: "a.www.example.com", want: true},
+		{name: "apex and wildcard", patterns: []string{"example.com", "*.example.com"}, domain: "example.com", want: true},
+		{name: "trailing dot in domain", patterns: []string{"www.example.com"}, domain: "www.example.com.", want: true},
+		{name: "trailing dot in pattern", patterns: []string{"www.example.com."}, domain: "www.example.com", want: true},
+		{name: "trailing dot in wildcard", patterns: []string{"*.example.com."}, domain: "www.example.com.", want: true},
+		{name: "upper case domain", patterns: []string{"www.example.com"}, domain: "WWW.Example.COM", want: true},
+		{name: "upper case pattern", patterns: []string{"*.EXAMPLE.com"}, domain: "www.example.com", want: true},
+		{name: "no patterns", patterns: nil, domain: "www.example.com", want: false},
+		{name: "second pattern", patterns: []string{"*.example.org", "*.example.com"}, domain: "www.example.com", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domainPermitted(tt.patterns, tt.domain); got != tt.want {
+				t.Errorf("domainPermitted(%q, %q) = %v, want %v", tt.patterns, tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyViolation(t *testing.T) {
+	const otherCAARN = "arn:aws:acm-pca:us-west-2:111122223333:certificate-authority/other"
+	tests := []struct {
+		name   string
+		policy svcapitypes.CertificatePolicySpec
+		req    policyRequest
+		// wantViolation is a substring of the violation, or empty if the
+		// request is permitted.
+		wantViolation string
+	}{
+		{
+			name:   "empty policy permits everything",
+			policy: svcapitypes.CertificatePolicySpec{},
+			req:    policyRequest{domains: []string{"www.example.com"}, keyAlgorithm: "RSA_2048"},
+		},
+		{
+			name:   "all domains permitted",
+			policy: svcapitypes.CertificatePolicySpec{DomainPatterns: []string{"example.com", "*.example.com"}},
+			req:    policyRequest{domains: []string{"example.com", "www.example.com"}},
+		},
+		{
+			name:          "one domain not permitted",
+			policy:        svcapitypes.CertificatePolicySpec{DomainPatterns: []string{"*.example.com"}},
+			req:           policyRequest{domains: []string{"www.example.com", "example.com"}},
+			wantViolation: "domain example.com does not match any of *.example.com",
+		},
+		{
+			name: "public certificate with certificate authorities",
+			policy: svcapitypes.CertificatePolicySpec{
+				CertificateAuthorityARNs: []string{testCAARN},
+			},
+			req:           policyRequest{domains: []string{"www.example.com"}},
+			wantViolation: "public certificates are not permitted",
+		},
+		{
+			name: "permitted certificate authority",
+			policy: svcapitypes.CertificatePolicySpec{
+				CertificateAuthorityARNs: []string{otherCAARN, testCAARN},
+			},
+			req: policyRequest{certificateAuthorityARN: testCAARN},
+		},
+		{
+			name: "other certificate authority",
+			policy: svcapitypes.CertificatePolicySpec{
+				CertificateAuthorityARNs: []string{testCAARN},
+			},
+			req:           policyRequest{certificateAuthorityARN: otherCAARN},
+			wantViolation: "certificate authority " + otherCAARN + " is not permitted",
+		},
+		{
+			name: "unresolved certificate authority",
+			policy: svcapitypes.CertificatePolicySpec{
+				CertificateAuthorityARNs: []string{testCAARN},
+			},
+			req: policyRequest{certificateAuthorityUnresolved: true},
+		},
+		{
+			name: "certificate authorities do not apply to imports",
+			policy: svcapitypes.CertificatePolicySpec{
+				CertificateAuthorityARNs: []string{testCAARN},
+			},
+			req: policyRequest{imported: true, keyAlgorithm: "EC_prime256v1"},
+		},
+		{
+			name:   "permitted key algorithm",
+			policy: svcapitypes.CertificatePolicySpec{KeyAlgorithms: []string{"RSA_2048", "EC_prime256v1"}},
+			req:    policyRequest{keyAlgorithm: "EC_prime256v1"},
+		},
+		{
+			name:   "key algorithm as described by ACM",
+			policy: svcapitypes.CertificatePolicySpec{KeyAlgorithms: []string{"RSA-2048"}},
+			req:    policyRequest{keyAlgorithm: "RSA_2048"},
+		},
+		{
+			name:          "key algorithm not permitted",
+			policy:        svcapitypes.CertificatePolicySpec{KeyAlgorithms: []string{"RSA_2048"}},
+			req:           policyRequest{keyAlgorithm: "RSA_1024"},
+			wantViolation: "key algorithm RSA_1024 is not permitted",
+		},
+		{
+			name: "domains are checked first",
+			policy: svcapitypes.CertificatePolicySpec{
+				DomainPatterns: []string{"www.example.com"},
+				KeyAlgorithms:  []string{"RSA_2048"},
+			},
+			req:           policyRequest{domains: []string{"api.example.com"}, keyAlgorithm: "RSA_1024"},
+			wantViolation: "domain api.example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policyViolation(&tt.policy, tt.req)
+			if tt.wantViolation == "" {
+				if got != "" {
+					t.Errorf("policyViolation() = %q, want the request permitted", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.wantViolation) {
+				t.Errorf("policyViolation() = %q, want %q", got, tt.wantViolation)
+			}
+		})
+	}
+}
+
+func TestEvaluateCertificatePolicies(t *testing.T) {
+	const namespace = "team-a"
+	req := policyRequest{domains: []string{"www.example.com"}, keyAlgorithm: "RSA_2048"}
+	policy := func(name string, spec svcapitypes.CertificatePolicySpec) client.Object {
+		return &svcapitypes.CertificatePolicy{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+	selector := func(labels map[string]string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: labels}
+	}
+	permitting := []string{"*.example.com"}
+	denying := []string{"*.example.org"}
+	namespaceObject := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   namespace,
+		Labels: map[string]string{"team": "a"},
+	}}
+	tests := []struct {
+		name    string
+		objects []client.Object
+		// wantViolation is a substring of the violation, or empty if the
+		// request is permitted.
+		wantViolation    string
+		wantApproval     bool
+		wantErrSubstring string
+	}{
+		{
+			name:    "no policies",
+			objects: []client.Object{namespaceObject},
+		},
+		{
+			name: "policy for the namespace by name",
+			objects: []client.Object{namespaceObject,
+				policy("by-name", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: permitting}),
+			},
+		},
+		{
+			name: "policy for the namespace by selector",
+			objects: []client.Object{namespaceObject,
+				policy("by-selector", svcapitypes.CertificatePolicySpec{
+					NamespaceSelector: selector(map[string]string{"team": "a"}),
+					DomainPatterns:    permitting,
+				}),
+			},
+		},
+		{
+			name: "empty selector selects every namespace",
+			objects: []client.Object{namespaceObject,
+				policy("everyone", svcapitypes.CertificatePolicySpec{
+					NamespaceSelector: &metav1.LabelSelector{},
+					DomainPatterns:    permitting,
+				}),
+			},
+		},
+		{
+			name: "selector applies in addition to names",
+			objects: []client.Object{namespaceObject,
+				policy("names-and-selector", svcapitypes.CertificatePolicySpec{
+					Namespaces:        []string{"team-b"},
+					NamespaceSelector: selector(map[string]string{"team": "a"}),
+					DomainPatterns:    permitting,
+				}),
+			},
+		},
+		{
+			name: "no policy applies",
+			objects: []client.Object{namespaceObject,
+				policy("other-name", svcapitypes.CertificatePolicySpec{Namespaces: []string{"team-b"}, DomainPatterns: permitting}),
+				policy("other-labels", svcapitypes.CertificatePolicySpec{
+					NamespaceSelector: selector(map[string]string{"team": "b"}),
+					DomainPatterns:    permitting,
+				}),
+			},
+			wantViolation: "no CertificatePolicy applies to namespace team-a",
+		},
+		{
+			name: "applying policy denies",
+			objects: []client.Object{namespaceObject,
+				policy("deny", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: denying}),
+			},
+			wantViolation: "CertificatePolicy deny: domain www.example.com",
+		},
+		{
+			name: "every applying policy denies",
+			objects: []client.Object{namespaceObject,
+				policy("deny-a", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: denying}),
+				policy("deny-b", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, KeyAlgorithms: []string{"EC_prime256v1"}}),
+			},
+			wantViolation: "CertificatePolicy deny-a: domain www.example.com does not match any of *.example.org; CertificatePolicy deny-b: key algorithm",
+		},
+		{
+			name: "one applying policy permits",
+			objects: []client.Object{namespaceObject,
+				policy("deny", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: denying}),
+				policy("permit", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: permitting}),
+			},
+		},
+		{
+			name: "permitting policy of another namespace",
+			objects: []client.Object{namespaceObject,
+				policy("deny", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, DomainPatterns: denying}),
+				policy("permit-b", svcapitypes.CertificatePolicySpec{Namespaces: []string{"team-b"}, DomainPatterns: permitting}),
+			},
+			wantViolation: "CertificatePolicy deny:",
+		},
+		{
+			name: "permitted with approval",
+			objects: []client.Object{namespaceObject,
+				policy("approval", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, RequireApproval: true}),
+			},
+			wantApproval: true,
+		},
+		{
+			name: "permitted without approval by another policy",
+			objects: []client.Object{namespaceObject,
+				policy("approval", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}, RequireApproval: true}),
+				policy("no-approval", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}}),
+			},
+		},
+		{
+			name: "approval of a denying policy does not apply",
+			objects: []client.Object{namespaceObject,
+				policy("approval", svcapitypes.CertificatePolicySpec{
+					Namespaces: []string{namespace}, DomainPatterns: denying, RequireApproval: true,
+				}),
+				policy("no-approval", svcapitypes.CertificatePolicySpec{Namespaces: []string{namespace}}),
+			},
+		},
+		{
+			name: "missing namespace",
+			objects: []client.Object{
+				policy("by-selector", svcapitypes.CertificatePolicySpec{NamespaceSelector: selector(map[string]string{"team": "a"})}),
+			},
+			wantErrSubstring: `reading labels of namespace "team-a"`,
+		},
+		{
+			name: "invalid selector",
+			objects: []client.Object{namespaceObject,
+				policy("invalid", svcapitypes.CertificatePolicySpec{NamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}},
+				}}),
+			},
+			wantErrSubstring: "CertificatePolicy invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := newFakeKubeClient(t, tt.objects...)
+			decision, err := evaluateCertificatePolicies(context.Background(), kc, namespace, req)
+			if tt.wantErrSubstring != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstring) {
+					t.Fatalf("evaluateCertificatePolicies error = %v, want %q", err, tt.wantErrSubstring)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evaluateCertificatePolicies: %v", err)
+			}
+			switch {
+			case tt.wantViolation == "" && decision.violation != "":
+				t.Errorf("violation = %q, want the request permitted", decision.violation)
+			case !strings.Contains(decision.violation, tt.wantViolation):
+				t.Errorf("violation = %q, want %q", decision.violation, tt.wantViolation)
+			}
+			if decision.violation == "" && decision.approvalRequired != tt.wantApproval {
+				t.Errorf("approvalRequired = %v, want %v", decision.approvalRequired, tt.wantApproval)
+			}
+		})
+	}
+}
+
+func TestImportReplicaChecksPolicies(t *testing.T) {
+	certPEM, keyPEM := selfSignedCertificate(t, "imported.example.org", time.Now().Add(24*time.Hour))
+	namespaceObject := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}}
+	policy := func(patterns ...string) client.Object {
+		return &svcapitypes.CertificatePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "imports"},
+			Spec:       svcapitypes.CertificatePolicySpec{Namespaces: []string{testNamespace}, DomainPatterns: patterns},
+		}
+	}
+	tests := []struct {
+		name         string
+		objects      []client.Object
+		wantTerminal bool
+	}{
+		{name: "no policies", objects: []client.Object{namespaceObject}},
+		{name: "permitted", objects: []client.Object{namespaceObject, policy("*.example.org")}},
+		{name: "not permitted", objects: []client.Object{namespaceObject, policy("*.example.com")}, wantTerminal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, _, rr := newTestResourceManager(t)
+			kc := newFakeKubeClient(t, tt.objects...)
+			bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+			rr.setSecret("import", "tls.crt", certPEM)
+			rr.setSecret("import", "tls.key", keyPEM)
+			desired := newTestCertificate(svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+			})
+
+			arn, err := rm.importReplica(context.Background(), desired, desired.ko, "us-east-1", "")
+			if tt.wantTerminal {
+				if !isTerminal(err) || !strings.Contains(err.Error(), "CertificatePolicy imports") {
+					t.Fatalf("importReplica error = %v, want a Terminal policy violation", err)
+				}
+				if arn != "" {
+					t.Errorf("importReplica imported %s", arn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importReplica: %v", err)
+			}
+			if arn == "" {
+				t.Error("importReplica returned no ARN")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch
+
+// certManagerCertificateGVK is the kind of the cert-manager Certificates
+// referenced by CertManagerCertificateRef. They are read as unstructured
+// objects so that the controller does not depend on cert-manager's API
+// module.
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// certManagerCertificateName returns the namespace and name of the
+// cert-manager Certificate referenced by a Certificate in the supplied
+// namespace.
+func certManagerCertificateName(
+	namespace string,
+	ref *svcapitypes.CertManagerCertificateReference,
+) types.NamespacedName {
+	nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if nn.Namespace == "" {
+		nn.Namespace = namespace
+	}
+	return nn
+}
+
+// certManagerCertificateSecretName returns the namespace and name of the
+// Secret cert-manager writes the referenced Certificate to.
+func certManagerCertificateSecretName(
+	ctx context.Context,
+	kc client.Client,
+	namespace string,
+	ref *svcapitypes.CertManagerCertificateReference,
+) (types.NamespacedName, error) {
+	nn := certManagerCertificateName(namespace, ref)
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(certManagerCertificateGVK)
+	if err := kc.Get(ctx, nn, obj); err != nil {
+		return types.NamespacedName{}, ackrequeue.Needed(fmt.Errorf(
+			"reading cert-manager Certificate %s: %w", nn, err,
+		))
+	}
+	secretName, _, err := unstructured.NestedString(obj.Object, "spec", "secretName")
+	if err != nil || secretName == "" {
+		return types.NamespacedName{}, ackrequeue.Needed(fmt.Errorf(
+			"cert-manager Certificate %s has no spec.secretName", nn,
+		))
+	}
+	return types.NamespacedName{Namespace: nn.Namespace, Name: secretName}, nil
+}
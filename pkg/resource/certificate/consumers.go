@@ -0,0 +1,301 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;patch;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;patch;watch
+
+const (
+	// ConsumerKindIngress is the kind of consumer whose
+	// alb.ingress.kubernetes.io/certificate-arn annotation lists the ARN.
+	ConsumerKindIngress = "Ingress"
+	// ConsumerKindService is the kind of consumer whose
+	// service.beta.kubernetes.io/aws-load-balancer-ssl-cert annotation lists
+	// the ARN.
+	ConsumerKindService = "Service"
+)
+
+var (
+	// consumerAnnotations are the AWS Load Balancer Controller annotations
+	// holding the comma separated certificate ARNs of each kind of consumer.
+	consumerAnnotations = map[string]string{
+		ConsumerKindIngress: "alb.ingress.kubernetes.io/certificate-arn",
+		ConsumerKindService: "service.beta.kubernetes.io/aws-load-balancer-ssl-cert",
+	}
+)
+
+// findConsumer returns the entry of the supplied list for the consumer with
+// the supplied kind and name, or nil.
+func findConsumer(
+	consumers []*svcapitypes.CertificateConsumerStatus,
+	kind string,
+	name string,
+) *svcapitypes.CertificateConsumerStatus {
+	for _, c := range consumers {
+		if c.Kind == kind && c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// wantedConsumers returns the consumers whose annotation should list the
+// certificate: once it is issued, every consumer in Spec.Consumers of
+// desired with the ARN observed in latest, and until then only the
+// consumers in Status.AnnotatedConsumers that are still listed, so that a
+// certificate that is not validated yet is never attached to a load
+// balancer.
+func wantedConsumers(
+	desired *svcapitypes.Certificate,
+	latest *svcapitypes.Certificate,
+) []*svcapitypes.CertificateConsumerStatus {
+	arn := ""
+	if latest.Status.Status != nil &&
+		*latest.Status.Status == string(svcapitypes.CertificateStatus_SDK_ISSUED) &&
+		latest.Status.ACKResourceMetadata != nil &&
+		latest.Status.ACKResourceMetadata.ARN != nil {
+		arn = string(*latest.Status.ACKResourceMetadata.ARN)
+	}
+	wanted := []*svcapitypes.CertificateConsumerStatus{}
+	for _, c := range desired.Spec.Consumers {
+		if c == nil || findConsumer(wanted, c.Kind, c.Name) != nil {
+			continue
+		}
+		if arn != "" {
+			wanted = append(wanted, &svcapitypes.CertificateConsumerStatus{
+				Kind:           c.Kind,
+				Name:           c.Name,
+				CertificateARN: arn,
+			})
+			continue
+		}
+		if recorded := findConsumer(latest.Status.AnnotatedConsumers, c.Kind, c.Name); recorded != nil {
+			wanted = append(wanted, recorded)
+		}
+	}
+	return wanted
+}
+
+// compareConsumers forces an update while the annotations of the consumers
+// do not list the ARN of the issued certificate, or still list it for
+// consumers that were removed from Spec.Consumers, so that sdkUpdate gets a
+// chance to patch them.
+func compareConsumers(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	wanted := wantedConsumers(a.ko, b.ko)
+	observed := b.ko.Status.AnnotatedConsumers
+	if len(wanted) == 0 && len(observed) == 0 {
+		return
+	}
+	// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+	// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+	if !equality.Semantic.DeepEqual(wanted, observed) {
+		delta.Add("Spec.Status.AnnotatedConsumers", wanted, observed)
+	}
+}
+
+// syncConsumers adds the ARN of the issued certificate to the annotation of
+// each consumer in Spec.Consumers, replacing the ARN it added before if the
+// certificate was replaced, and removes it from consumers that are no
+// longer listed. The returned resource records the annotated consumers in
+// Status.AnnotatedConsumers, including when an error is returned, in which
+// case the consumers that could not be patched are retried after a delay.
+func (rm *resourceManager) syncConsumers(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncConsumers")
+	defer func() { exit(err) }()
+
+	ko := desired.ko.DeepCopy()
+	ko.Status.AnnotatedConsumers = latest.ko.Status.AnnotatedConsumers
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return &resource{ko}, err
+	}
+	wanted := wantedConsumers(desired.ko, latest.ko)
+	recorded := latest.ko.Status.AnnotatedConsumers
+
+	annotated := []*svcapitypes.CertificateConsumerStatus{}
+	var errs []error
+	for _, c := range recorded {
+		if findConsumer(wanted, c.Kind, c.Name) != nil {
+			continue
+		}
+		if err := patchConsumerAnnotation(ctx, kc, ko.Namespace, c.Kind, c.Name, "", c.CertificateARN); err != nil {
+			errs = append(errs, err)
+			annotated = append(annotated, c)
+			continue
+		}
+		rlog.Info("removed certificate ARN from consumer", "kind", c.Kind, "name", c.Name, "arn", c.CertificateARN)
+	}
+	for _, c := range wanted {
+		previous := findConsumer(recorded, c.Kind, c.Name)
+		if previous != nil && previous.CertificateARN == c.CertificateARN {
+			annotated = append(annotated, c)
+			continue
+		}
+		replaced := ""
+		if previous != nil {
+			replaced = previous.CertificateARN
+		}
+		if err := patchConsumerAnnotation(ctx, kc, ko.Namespace, c.Kind, c.Name, c.CertificateARN, replaced); err != nil {
+			errs = append(errs, err)
+			if previous != nil {
+				annotated = append(annotated, previous)
+			}
+			continue
+		}
+		rlog.Info("added certificate ARN to consumer", "kind", c.Kind, "name", c.Name, "arn", c.CertificateARN)
+		annotated = append(annotated, c)
+	}
+	if len(annotated) == 0 {
+		annotated = nil
+	}
+	ko.Status.AnnotatedConsumers = annotated
+	if len(errs) > 0 {
+		return &resource{ko}, ackrequeue.NeededAfter(errors.Join(errs...), requeuePending)
+	}
+	return &resource{ko}, nil
+}
+
+// releaseConsumers removes the ARN of a certificate that is being deleted
+// from the annotations of its consumers, so that the AWS Load Balancer
+// Controller stops using it and ACM lets it be deleted.
+func (rm *resourceManager) releaseConsumers(
+	ctx context.Context,
+	r *resource,
+) error {
+	if len(r.ko.Status.AnnotatedConsumers) == 0 {
+		return nil
+	}
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, c := range r.ko.Status.AnnotatedConsumers {
+		errs = append(errs, patchConsumerAnnotation(ctx, kc, r.ko.Namespace, c.Kind, c.Name, "", c.CertificateARN))
+	}
+	return errors.Join(errs...)
+}
+
+// patchConsumerAnnotation adds the ARN add to the certificate ARN annotation
+// of the supplied consumer, in place of the ARN remove if it is listed, or
+// only removes remove if add is empty. A consumer that does not exist is
+// only an error when an ARN is added to it.
+func patchConsumerAnnotation(
+	ctx context.Context,
+	kc client.Client,
+	namespace string,
+	kind string,
+	name string,
+	add string,
+	remove string,
+) error {
+	var obj client.Object
+	switch kind {
+	case ConsumerKindIngress:
+		obj = &networkingv1.Ingress{}
+	case ConsumerKindService:
+		obj = &corev1.Service{}
+	default:
+		return ackerr.NewTerminalError(fmt.Errorf("unsupported consumer kind %q", kind))
+	}
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	if err := kc.Get(ctx, nn, obj); err != nil {
+		if apierrors.IsNotFound(err) && add == "" {
+			return nil
+		}
+		return fmt.Errorf("reading %s %s: %w", kind, nn, err)
+	}
+	annotation := consumerAnnotations[kind]
+	current := obj.GetAnnotations()[annotation]
+	value := mergeCertificateARNs(current, add, remove)
+	if value == current {
+		return nil
+	}
+	if planDryRun(ctx, "set annotation %s of %s %s to %q", annotation, kind, nn, value) {
+		return nil
+	}
+	// The optimistic lock makes the patch fail rather than overwrite ARNs
+	// added to the annotation since it was read.
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if value == "" {
+		delete(annotations, annotation)
+	} else {
+		annotations[annotation] = value
+	}
+	obj.SetAnnotations(annotations)
+	if err := kc.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("patching %s %s: %w", kind, nn, err)
+	}
+	return nil
+}
+
+// mergeCertificateARNs returns the supplied comma separated list of ARNs
+// with add in place of remove, or appended if remove is not listed. The
+// order of the other ARNs is kept, since the AWS Load Balancer Controller
+// uses the first ARN of an Ingress as the default certificate of its
+// listeners. An empty add only removes remove.
+func mergeCertificateARNs(list string, add string, remove string) string {
+	arns := []string{}
+	added := add == ""
+	for _, arn := range strings.Split(list, ",") {
+		arn = strings.TrimSpace(arn)
+		switch {
+		case arn == "":
+		case arn == remove || arn == add:
+			if !added {
+				arns = append(arns, add)
+				added = true
+			}
+		default:
+			arns = append(arns, arn)
+		}
+	}
+	if !added {
+		arns = append(arns, add)
+	}
+	return strings.Join(arns, ",")
+}
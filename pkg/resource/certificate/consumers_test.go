@@ -0,0 +1,198 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestMergeCertificateARNs(t *testing.T) {
+	tests := []struct {
+		name   string
+		list   string
+		add    string
+		remove string
+		want   string
+	}{
+		{name: "add to empty list", list: "", add: "new", want: "new"},
+		{name: "append", list: "a,b", add: "new", want: "a,b,new"},
+		{name: "append when remove is not listed", list: "a,b", add: "new", remove: "old", want: "a,b,new"},
+		{name: "replace first in place", list: "old,a,b", add: "new", remove: "old", want: "new,a,b"},
+		{name: "replace middle in place", list: "a,old,b", add: "new", remove: "old", want: "a,new,b"},
+		{name: "replace last in place", list: "a,b,old", add: "new", remove: "old", want: "a,b,new"},
+		{name: "already listed", list: "a,new,b", add: "new", want: "a,new,b"},
+		{name: "already listed before remove", list: "new,a,old", add: "new", remove: "old", want: "new,a"},
+		{name: "already listed after remove", list: "old,a,new", add: "new", remove: "old", want: "new,a"},
+		{name: "add equal to remove", list: "a,old", add: "old", remove: "old", want: "a,old"},
+		{name: "remove only", list: "a,old,b", remove: "old", want: "a,b"},
+		{name: "remove the only ARN", list: "old", remove: "old", want: ""},
+		{name: "remove unlisted", list: "a,b", remove: "old", want: "a,b"},
+		{name: "nothing to add or remove", list: "a,b", want: "a,b"},
+		{name: "duplicates of add", list: "new,a,new,b,new", add: "new", want: "new,a,b"},
+		{name: "duplicates of remove", list: "a,old,b,old", add: "new", remove: "old", want: "a,new,b"},
+		{name: "duplicates of remove without add", list: "old,a,old", remove: "old", want: "a"},
+		{name: "duplicates of other ARNs are kept", list: "a,a,old", add: "new", remove: "old", want: "a,a,new"},
+		{name: "whitespace around ARNs", list: " a , old ,b ", add: "new", remove: "old", want: "a,new,b"},
+		{name: "empty entries", list: "a,,old,", add: "new", remove: "old", want: "a,new"},
+		{name: "whitespace only", list: "  ", add: "new", want: "new"},
+		{name: "whitespace only without add", list: " , ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeCertificateARNs(tt.list, tt.add, tt.remove); got != tt.want {
+				t.Errorf("mergeCertificateARNs(%q, %q, %q) = %q, want %q", tt.list, tt.add, tt.remove, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWantedConsumers(t *testing.T) {
+	const (
+		arn    = "arn:aws:acm:us-west-2:111122223333:certificate/new"
+		oldARN = "arn:aws:acm:us-west-2:111122223333:certificate/old"
+	)
+	ingress := &svcapitypes.CertificateConsumer{Kind: ConsumerKindIngress, Name: "web"}
+	service := &svcapitypes.CertificateConsumer{Kind: ConsumerKindService, Name: "web"}
+	annotated := func(c *svcapitypes.CertificateConsumer, arn string) *svcapitypes.CertificateConsumerStatus {
+		return &svcapitypes.CertificateConsumerStatus{Kind: c.Kind, Name: c.Name, CertificateARN: arn}
+	}
+	tests := []struct {
+		name      string
+		consumers []*svcapitypes.CertificateConsumer
+		// status and arn are the status and ARN of the latest certificate.
+		status    string
+		arn       string
+		annotated []*svcapitypes.CertificateConsumerStatus
+		want      []*svcapitypes.CertificateConsumerStatus
+	}{
+		{
+			name:      "pending certificate",
+			consumers: []*svcapitypes.CertificateConsumer{ingress},
+			status:    string(svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION),
+			arn:       arn,
+			want:      []*svcapitypes.CertificateConsumerStatus{},
+		},
+		{
+			name:      "pending replacement keeps the annotated ARN",
+			consumers: []*svcapitypes.CertificateConsumer{ingress, service},
+			status:    string(svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+			want:      []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+		},
+		{
+			name:      "pending certificate drops removed consumers",
+			consumers: []*svcapitypes.CertificateConsumer{service},
+			status:    string(svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+			want:      []*svcapitypes.CertificateConsumerStatus{},
+		},
+		{
+			name:      "issued certificate",
+			consumers: []*svcapitypes.CertificateConsumer{ingress, service},
+			status:    string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			arn:       arn,
+			want:      []*svcapitypes.CertificateConsumerStatus{annotated(ingress, arn), annotated(service, arn)},
+		},
+		{
+			name:      "issued replacement replaces the annotated ARN",
+			consumers: []*svcapitypes.CertificateConsumer{ingress},
+			status:    string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+			want:      []*svcapitypes.CertificateConsumerStatus{annotated(ingress, arn)},
+		},
+		{
+			name:      "issued certificate drops removed consumers",
+			consumers: []*svcapitypes.CertificateConsumer{service},
+			status:    string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, arn), annotated(service, arn)},
+			want:      []*svcapitypes.CertificateConsumerStatus{annotated(service, arn)},
+		},
+		{
+			name:      "no consumers",
+			status:    string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, arn)},
+			want:      []*svcapitypes.CertificateConsumerStatus{},
+		},
+		{
+			name: "duplicate and nil consumers",
+			consumers: []*svcapitypes.CertificateConsumer{
+				ingress, nil, {Kind: ConsumerKindIngress, Name: "web"}, service, ingress,
+			},
+			status: string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			arn:    arn,
+			want:   []*svcapitypes.CertificateConsumerStatus{annotated(ingress, arn), annotated(service, arn)},
+		},
+		{
+			name:      "same name of another kind",
+			consumers: []*svcapitypes.CertificateConsumer{service},
+			status:    string(svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION),
+			arn:       arn,
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+			want:      []*svcapitypes.CertificateConsumerStatus{},
+		},
+		{
+			name:      "issued certificate without an ARN",
+			consumers: []*svcapitypes.CertificateConsumer{ingress},
+			status:    string(svcapitypes.CertificateStatus_SDK_ISSUED),
+			annotated: []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+			want:      []*svcapitypes.CertificateConsumerStatus{annotated(ingress, oldARN)},
+		},
+		{
+			name:      "failed certificate",
+			consumers: []*svcapitypes.CertificateConsumer{ingress},
+			status:    string(svcapitypes.CertificateStatus_SDK_FAILED),
+			arn:       arn,
+			want:      []*svcapitypes.CertificateConsumerStatus{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &svcapitypes.Certificate{}
+			desired.Spec.Consumers = tt.consumers
+			latest := &svcapitypes.Certificate{}
+			latest.Status.Status = aws.String(tt.status)
+			latest.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{}
+			if tt.arn != "" {
+				arn := ackv1alpha1.AWSResourceName(tt.arn)
+				latest.Status.ACKResourceMetadata.ARN = &arn
+			}
+			latest.Status.AnnotatedConsumers = tt.annotated
+
+			got := wantedConsumers(desired, latest)
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("wantedConsumers() = %s, want %s", consumerStatuses(got), consumerStatuses(tt.want))
+			}
+		})
+	}
+}
+
+// consumerStatuses formats the supplied consumers for test failures.
+func consumerStatuses(consumers []*svcapitypes.CertificateConsumerStatus) []svcapitypes.CertificateConsumerStatus {
+	out := []svcapitypes.CertificateConsumerStatus{}
+	for _, c := range consumers {
+		out = append(out, *c)
+	}
+	return out
+}
@@ -0,0 +1,260 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	flag "github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/acm-controller/pkg/version"
+)
+
+const flagDefaultTags = "default-tags"
+
+var (
+	// defaultTags are the key=value pairs set with the --default-tags flag.
+	// Unlike the tags set with --resource-tags, which the controller owns,
+	// these are organization defaults that a Certificate can override by
+	// setting a tag with the same key.
+	defaultTags []string
+
+	// namespaceLabelFormat matches the placeholders in default tag values
+	// that are replaced by the value of a label of the namespace of the
+	// Certificate, e.g. %K8S_NAMESPACE_LABEL:team%.
+	namespaceLabelFormat = regexp.MustCompile(`%K8S_NAMESPACE_LABEL:([^%]+)%`)
+)
+
+func init() {
+	flag.StringSliceVar(
+		&defaultTags, flagDefaultTags, nil,
+		"Default key=value tags added to every certificate unless the "+
+			"Certificate sets a tag with the same key. Values can use the "+
+			"same formats as --resource-tags, and %K8S_NAMESPACE_LABEL:<key>% "+
+			"for the value of a label of the namespace of the Certificate.",
+	)
+}
+
+// ensureTags adds the tags set with --resource-tags to the supplied
+// resource. The default tags set with --default-tags are not added to the
+// spec: they are merged into the tags sent to ACM by awsTags, so that
+// changing or removing a default is applied to existing certificates.
+func (rm *resourceManager) ensureTags(
+	ctx context.Context,
+	r *resource,
+	md acktypes.ServiceControllerMetadata,
+) error {
+	controllerTags := ackrt.GetDefaultTags(&rm.cfg, r.ko, md)
+	resourceTags, keyOrder := convertToOrderedACKTags(r.ko.Spec.Tags)
+	tags := acktags.Merge(resourceTags, controllerTags)
+	r.ko.Spec.Tags = fromACKTags(tags, keyOrder)
+	return nil
+}
+
+// awsTags returns the tags the certificate of the supplied resource should
+// have in ACM: the tags in its spec and the default tags it does not
+// override.
+func (rm *resourceManager) awsTags(
+	ctx context.Context,
+	r *resource,
+) ([]*svcapitypes.Tag, error) {
+	defaults, err := rm.applicableDefaultTags(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	resourceTags, keyOrder := convertToOrderedACKTags(r.ko.Spec.Tags)
+	return fromACKTags(acktags.Merge(resourceTags, defaults), keyOrder), nil
+}
+
+// sdkTags returns the tags the certificate of the supplied resource should
+// have in ACM as set in RequestCertificate and ImportCertificate inputs.
+func (rm *resourceManager) sdkTags(
+	ctx context.Context,
+	r *resource,
+) ([]svcsdktypes.Tag, error) {
+	tags, err := rm.awsTags(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	sdkTags := make([]svcsdktypes.Tag, 0, len(tags))
+	for _, tag := range tags {
+		sdkTags = append(sdkTags, svcsdktypes.Tag{Key: tag.Key, Value: tag.Value})
+	}
+	return sdkTags, nil
+}
+
+// awsTagSets returns the tags the certificate should have in ACM according
+// to the desired resource, and the tags it has according to the latest
+// resource, whose spec lacks the default tags hidden by specTags.
+func (rm *resourceManager) awsTagSets(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (desiredTags []*svcapitypes.Tag, latestTags []*svcapitypes.Tag, err error) {
+	if desiredTags, err = rm.awsTags(ctx, desired); err != nil {
+		return nil, nil, err
+	}
+	defaults, err := rm.applicableDefaultTags(ctx, latest)
+	if err != nil {
+		return nil, nil, err
+	}
+	resourceTags, keyOrder := convertToOrderedACKTags(latest.ko.Spec.Tags)
+	for key, value := range defaults {
+		if _, found := resourceTags[key]; !found {
+			resourceTags[key] = value
+		}
+	}
+	return desiredTags, fromACKTags(resourceTags, keyOrder), nil
+}
+
+// specTags returns the supplied tags of the certificate of the supplied
+// resource in ACM as they appear in its spec: default tags the spec does
+// not set are left out when ACM has them with their default value, and are
+// listed with an empty value otherwise, so that the delta with the desired
+// spec reports them and sdkUpdate sets them.
+func (rm *resourceManager) specTags(
+	ctx context.Context,
+	r *resource,
+	tags []*svcapitypes.Tag,
+) ([]*svcapitypes.Tag, error) {
+	defaults, err := rm.applicableDefaultTags(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(defaults) == 0 {
+		return tags, nil
+	}
+	resourceTags, keyOrder := convertToOrderedACKTags(tags)
+	for key, value := range defaults {
+		if resourceTags[key] == value {
+			delete(resourceTags, key)
+		} else {
+			resourceTags[key] = ""
+		}
+	}
+	return fromACKTags(resourceTags, keyOrder), nil
+}
+
+// applicableDefaultTags returns the default tags set with --default-tags
+// that apply to the supplied resource, i.e. those whose key its spec does
+// not set.
+func (rm *resourceManager) applicableDefaultTags(
+	ctx context.Context,
+	r *resource,
+) (acktags.Tags, error) {
+	defaults, err := rm.expandDefaultTags(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range r.ko.Spec.Tags {
+		if tag != nil && tag.Key != nil {
+			delete(defaults, *tag.Key)
+		}
+	}
+	return defaults, nil
+}
+
+// filterSystemTags removes the tags that are not managed through the spec
+// of the supplied resource: tags starting with "aws:", the tags set with
+// --resource-tags, and the default tags set with --default-tags.
+func (rm *resourceManager) filterSystemTags(r *resource, systemTags []string) {
+	resourceTags, tagKeyOrder := convertToOrderedACKTags(r.ko.Spec.Tags)
+	ignoreSystemTags(resourceTags, slices.Concat(systemTags, defaultTagKeys()))
+	r.ko.Spec.Tags = fromACKTags(resourceTags, tagKeyOrder)
+}
+
+// defaultTagKeys returns the keys of the tags set with --default-tags.
+func defaultTagKeys() []string {
+	keys := []string{}
+	for _, tagKeyVal := range defaultTags {
+		if key, _, found := strings.Cut(tagKeyVal, "="); found && strings.TrimSpace(key) != "" {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+	return keys
+}
+
+// expandDefaultTags returns the tags set with --default-tags for the
+// supplied resource, with namespace label placeholders replaced by the
+// labels of its namespace and the other formats expanded by the runtime.
+func (rm *resourceManager) expandDefaultTags(
+	ctx context.Context,
+	r *resource,
+) (acktags.Tags, error) {
+	if len(defaultTags) == 0 {
+		return acktags.NewTags(), nil
+	}
+	var labels map[string]string
+	resourceTags := make([]string, 0, len(defaultTags))
+	for _, tagKeyVal := range defaultTags {
+		if namespaceLabelFormat.MatchString(tagKeyVal) && labels == nil {
+			var err error
+			if labels, err = rm.namespaceLabels(ctx, r.ko.GetNamespace()); err != nil {
+				return nil, err
+			}
+		}
+		resourceTags = append(resourceTags, namespaceLabelFormat.ReplaceAllStringFunc(
+			tagKeyVal,
+			func(placeholder string) string {
+				return labels[namespaceLabelFormat.FindStringSubmatch(placeholder)[1]]
+			},
+		))
+	}
+	return ackrt.GetDefaultTags(&ackcfg.Config{ResourceTags: resourceTags}, r.ko, serviceControllerMetadata()), nil
+}
+
+// serviceControllerMetadata returns the metadata of the controller that the
+// runtime expands the formats of tag values with.
+func serviceControllerMetadata() acktypes.ServiceControllerMetadata {
+	return acktypes.ServiceControllerMetadata{
+		VersionInfo: acktypes.VersionInfo{
+			GitCommit:  version.GitCommit,
+			GitVersion: version.GitVersion,
+			BuildDate:  version.BuildDate,
+		},
+		ServiceAlias:    "acm",
+		ServiceAPIGroup: svcapitypes.GroupVersion.Group,
+	}
+}
+
+// namespaceLabels returns the labels of the supplied namespace.
+func (rm *resourceManager) namespaceLabels(ctx context.Context, name string) (map[string]string, error) {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	ns := &corev1.Namespace{}
+	if err := kc.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+		return nil, fmt.Errorf("reading labels of namespace %q for default tags: %w", name, err)
+	}
+	if ns.Labels == nil {
+		return map[string]string{}, nil
+	}
+	return ns.Labels, nil
+}
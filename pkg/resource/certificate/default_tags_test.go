@@ -0,0 +1,123 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// setDefaultTags sets the --default-tags flag for the duration of the test.
+func setDefaultTags(t *testing.T, tags ...string) {
+	t.Helper()
+	previous := defaultTags
+	defaultTags = tags
+	t.Cleanup(func() { defaultTags = previous })
+}
+
+func TestApplicableDefaultTags(t *testing.T) {
+	labeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   testNamespace,
+		Labels: map[string]string{"team": "web", "cost-center": "1234"},
+	}}
+	tests := []struct {
+		name        string
+		defaultTags []string
+		namespace   *corev1.Namespace
+		specTags    []*svcapitypes.Tag
+		want        map[string]string
+		wantErr     bool
+	}{
+		{
+			name:      "no default tags",
+			namespace: labeled,
+			want:      map[string]string{},
+		},
+		{
+			name:        "namespace label",
+			defaultTags: []string{"team=%K8S_NAMESPACE_LABEL:team%"},
+			namespace:   labeled,
+			want:        map[string]string{"team": "web"},
+		},
+		{
+			name:        "several labels in one value",
+			defaultTags: []string{"owner=%K8S_NAMESPACE_LABEL:team%/%K8S_NAMESPACE_LABEL:cost-center%"},
+			namespace:   labeled,
+			want:        map[string]string{"owner": "web/1234"},
+		},
+		{
+			name:        "label and runtime formats",
+			defaultTags: []string{"owner=%K8S_NAMESPACE%-%K8S_NAMESPACE_LABEL:team%", "static=yes"},
+			namespace:   labeled,
+			want:        map[string]string{"owner": testNamespace + "-web", "static": "yes"},
+		},
+		{
+			name:        "missing label drops the tag",
+			defaultTags: []string{"team=%K8S_NAMESPACE_LABEL:owner%", "static=yes"},
+			namespace:   labeled,
+			want:        map[string]string{"static": "yes"},
+		},
+		{
+			name:        "unlabeled namespace",
+			defaultTags: []string{"team=%K8S_NAMESPACE_LABEL:team%"},
+			namespace:   &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+			want:        map[string]string{},
+		},
+		{
+			name:        "namespace not read without placeholders",
+			defaultTags: []string{"static=yes"},
+			want:        map[string]string{"static": "yes"},
+		},
+		{
+			name:        "missing namespace",
+			defaultTags: []string{"team=%K8S_NAMESPACE_LABEL:team%"},
+			wantErr:     true,
+		},
+		{
+			name:        "overridden by the spec",
+			defaultTags: []string{"team=%K8S_NAMESPACE_LABEL:team%", "static=yes"},
+			namespace:   labeled,
+			specTags:    []*svcapitypes.Tag{{Key: aws.String("team"), Value: aws.String("api")}},
+			want:        map[string]string{"static": "yes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDefaultTags(t, tt.defaultTags...)
+			rm, _, _ := newTestResourceManager(t)
+			var objs []client.Object
+			if tt.namespace != nil {
+				objs = append(objs, tt.namespace)
+			}
+			kc := newFakeKubeClient(t, objs...)
+			bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+
+			got, err := rm.applicableDefaultTags(context.Background(), newTestCertificate(svcapitypes.CertificateSpec{Tags: tt.specTags}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applicableDefaultTags error = %v, want error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("applicableDefaultTags = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -43,6 +43,13 @@ func newResourceDelta(
 	}
 	compareCertificateIssuedAt(delta, a, b)
 	compareKeyAlgorithm(delta, a, b)
+	compareSubjectAlternativeNames(delta, a, b)
+	compareReplacementStatus(delta, a, b)
+	compareImportSourceSerial(delta, a, b)
+	compareReplicas(delta, a, b)
+	compareValidationStatus(delta, a, b)
+	compareConsumers(delta, a, b)
+	compareGatewayListeners(delta, a, b)
 
 	if ackcompare.HasNilDifference(a.ko.Spec.CertificateARN, b.ko.Spec.CertificateARN) {
 		delta.Add("Spec.CertificateARN", a.ko.Spec.CertificateARN, b.ko.Spec.CertificateARN)
@@ -78,12 +85,12 @@ func newResourceDelta(
 				delta.Add("Spec.Options.CertificateTransparencyLoggingPreference", a.ko.Spec.Options.CertificateTransparencyLoggingPreference, b.ko.Spec.Options.CertificateTransparencyLoggingPreference)
 			}
 		}
-	}
-	if len(a.ko.Spec.SubjectAlternativeNames) != len(b.ko.Spec.SubjectAlternativeNames) {
-		delta.Add("Spec.SubjectAlternativeNames", a.ko.Spec.SubjectAlternativeNames, b.ko.Spec.SubjectAlternativeNames)
-	} else if len(a.ko.Spec.SubjectAlternativeNames) > 0 {
-		if !ackcompare.SliceStringPEqual(a.ko.Spec.SubjectAlternativeNames, b.ko.Spec.SubjectAlternativeNames) {
-			delta.Add("Spec.SubjectAlternativeNames", a.ko.Spec.SubjectAlternativeNames, b.ko.Spec.SubjectAlternativeNames)
+		if ackcompare.HasNilDifference(a.ko.Spec.Options.Export, b.ko.Spec.Options.Export) {
+			delta.Add("Spec.Options.Export", a.ko.Spec.Options.Export, b.ko.Spec.Options.Export)
+		} else if a.ko.Spec.Options.Export != nil && b.ko.Spec.Options.Export != nil {
+			if *a.ko.Spec.Options.Export != *b.ko.Spec.Options.Export {
+				delta.Add("Spec.Options.Export", a.ko.Spec.Options.Export, b.ko.Spec.Options.Export)
+			}
 		}
 	}
 	desiredACKTags, _ := convertToOrderedACKTags(a.ko.Spec.Tags)
@@ -0,0 +1,88 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestNewResourceDeltaOptionsExport(t *testing.T) {
+	tests := []struct {
+		name      string
+		desired   *svcapitypes.CertificateOptions
+		latest    *svcapitypes.CertificateOptions
+		wantPaths []string
+	}{
+		{
+			name:    "equal",
+			desired: &svcapitypes.CertificateOptions{Export: aws.String("ENABLED")},
+			latest:  &svcapitypes.CertificateOptions{Export: aws.String("ENABLED")},
+		},
+		{
+			name:      "changed",
+			desired:   &svcapitypes.CertificateOptions{Export: aws.String("ENABLED")},
+			latest:    &svcapitypes.CertificateOptions{Export: aws.String("DISABLED")},
+			wantPaths: []string{"Spec.Options.Export"},
+		},
+		{
+			name:      "unset",
+			desired:   &svcapitypes.CertificateOptions{},
+			latest:    &svcapitypes.CertificateOptions{Export: aws.String("DISABLED")},
+			wantPaths: []string{"Spec.Options.Export"},
+		},
+		{
+			name:      "set",
+			desired:   &svcapitypes.CertificateOptions{Export: aws.String("ENABLED")},
+			latest:    &svcapitypes.CertificateOptions{},
+			wantPaths: []string{"Spec.Options.Export"},
+		},
+		{
+			name:      "options unset",
+			latest:    &svcapitypes.CertificateOptions{Export: aws.String("DISABLED")},
+			wantPaths: []string{"Spec.Options"},
+		},
+		{
+			name: "logging preference changed",
+			desired: &svcapitypes.CertificateOptions{
+				CertificateTransparencyLoggingPreference: aws.String("DISABLED"),
+				Export:                                   aws.String("ENABLED"),
+			},
+			latest: &svcapitypes.CertificateOptions{
+				CertificateTransparencyLoggingPreference: aws.String("ENABLED"),
+				Export:                                   aws.String("ENABLED"),
+			},
+			wantPaths: []string{"Spec.Options.CertificateTransparencyLoggingPreference"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := newTestCertificate(svcapitypes.CertificateSpec{Options: tt.desired})
+			latest := newTestCertificate(svcapitypes.CertificateSpec{Options: tt.latest})
+
+			delta := newResourceDelta(desired, latest)
+			if len(delta.Differences) != len(tt.wantPaths) {
+				t.Fatalf("delta has %d differences, want %q", len(delta.Differences), tt.wantPaths)
+			}
+			for _, path := range tt.wantPaths {
+				if !delta.DifferentAt(path) {
+					t.Errorf("delta does not differ at %s", path)
+				}
+			}
+		})
+	}
+}
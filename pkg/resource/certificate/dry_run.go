@@ -0,0 +1,298 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	"github.com/aws/smithy-go/middleware"
+	flag "github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	flagDryRun = "dry-run"
+
+	// AnnotationDryRun is the annotation that makes the controller plan the
+	// changes to a Certificate without making them when set to "true".
+	AnnotationDryRun = "acm.services.k8s.aws/dry-run"
+
+	// eventReasonDryRunPlanned is the reason of the Events recorded for each
+	// change planned in dry-run mode.
+	eventReasonDryRunPlanned = "DryRunPlanned"
+
+	// requeueDryRun is how long a Certificate in dry-run mode waits before
+	// its changes are planned again.
+	requeueDryRun = 10 * time.Minute
+)
+
+var (
+	// dryRun is set with the --dry-run flag to plan the changes to every
+	// Certificate without making them.
+	dryRun bool
+
+	// dryRunRedactedFields are the fields of ACM inputs that are not written
+	// into dry-run plans, since they hold private keys, passphrases or
+	// whole PEM encoded certificates.
+	dryRunRedactedFields = []string{"Certificate", "CertificateChain", "Passphrase", "PrivateKey"}
+)
+
+func init() {
+	flag.BoolVar(
+		&dryRun, flagDryRun, false,
+		"Plan the ACM calls and Kubernetes changes made for every Certificate "+
+			"and record them in Status.DryRunPlan and Events instead of making "+
+			"them. A Certificate can also be planned only by setting the "+
+			AnnotationDryRun+" annotation to \"true\".",
+	)
+}
+
+// dryRunPlan collects the changes the controller would make to a
+// Certificate in dry-run mode.
+type dryRunPlan struct {
+	// accountID is the AWS account of the resource manager, used to make up
+	// the ARNs of planned certificates.
+	accountID string
+	// steps are the planned changes, in the order they would be made.
+	steps []*string
+}
+
+// add appends a planned change to the plan.
+func (p *dryRunPlan) add(format string, args ...interface{}) {
+	p.steps = append(p.steps, aws.String(fmt.Sprintf(format, args...)))
+}
+
+// dryRunPlanKey is the context key of the dryRunPlan of a reconciliation in
+// dry-run mode.
+type dryRunPlanKey struct{}
+
+// dryRunPlanFromContext returns the plan of the reconciliation of the
+// supplied context, or nil if it is not in dry-run mode.
+func dryRunPlanFromContext(ctx context.Context) *dryRunPlan {
+	plan, _ := ctx.Value(dryRunPlanKey{}).(*dryRunPlan)
+	return plan
+}
+
+// isDryRun returns true if the changes to the supplied resource should be
+// planned rather than made, and are not being planned already.
+func isDryRun(ctx context.Context, r *resource) bool {
+	if dryRunPlanFromContext(ctx) != nil {
+		return false
+	}
+	return dryRun || r.ko.GetAnnotations()[AnnotationDryRun] == "true"
+}
+
+// newDryRunContext returns a context that makes the ACM clients of the
+// planner record mutating calls into a new plan instead of sending them,
+// and the plan.
+func (rm *resourceManager) newDryRunContext(ctx context.Context) (context.Context, *dryRunPlan) {
+	plan := &dryRunPlan{accountID: string(rm.awsAccountID)}
+	return context.WithValue(ctx, dryRunPlanKey{}, plan), plan
+}
+
+// planner returns a copy of the resource manager whose ACM client has the
+// dry-run middleware, to run operations with a context from
+// newDryRunContext. The client of the resource manager itself is generated
+// without the middleware.
+func (rm *resourceManager) planner() *resourceManager {
+	planner := *rm
+	planner.sdkapi = svcsdk.New(rm.sdkapi.Options(), withDryRun)
+	return &planner
+}
+
+// planCreate runs sdkCreate in dry-run mode.
+func (rm *resourceManager) planCreate(
+	ctx context.Context,
+	desired *resource,
+) (*resource, error) {
+	ctx, plan := rm.newDryRunContext(ctx)
+	_, err := rm.planner().sdkCreate(ctx, desired)
+	return rm.planned(desired, desired, plan, err)
+}
+
+// planUpdate runs sdkUpdate in dry-run mode.
+func (rm *resourceManager) planUpdate(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, error) {
+	ctx, plan := rm.newDryRunContext(ctx)
+	_, err := rm.planner().sdkUpdate(ctx, desired, latest, delta)
+	return rm.planned(desired, latest, plan, err)
+}
+
+// planDelete runs sdkDelete in dry-run mode. The Certificate keeps its
+// finalizer until it is no longer in dry-run mode.
+func (rm *resourceManager) planDelete(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	ctx, plan := rm.newDryRunContext(ctx)
+	_, err := rm.planner().sdkDelete(ctx, r)
+	return rm.planned(r, r, plan, err)
+}
+
+// planned returns the spec of desired with the status of latest and the
+// supplied plan in Status.DryRunPlan, discarding whatever the planned
+// operation returned, and records an Event for each planned change. The
+// returned error is err, or an error requeueing the resource to be planned
+// again, so that the resource is never reported as synced.
+func (rm *resourceManager) planned(
+	desired *resource,
+	latest *resource,
+	plan *dryRunPlan,
+	err error,
+) (*resource, error) {
+	ko := desired.ko.DeepCopy()
+	latest.ko.Status.DeepCopyInto(&ko.Status)
+	ko.Status.DryRunPlan = plan.steps
+	for _, step := range plan.steps {
+		rm.recordEvent(ko, corev1.EventTypeNormal, eventReasonDryRunPlanned, "%s", *step)
+	}
+	if err != nil {
+		return &resource{ko}, err
+	}
+	return &resource{ko}, ackrequeue.NeededAfter(
+		fmt.Errorf("dry run: planned %d changes, see Status.DryRunPlan", len(plan.steps)),
+		requeueDryRun,
+	)
+}
+
+// withDryRun adds the middleware that records the mutating calls made in
+// dry-run mode to the options of an ACM client.
+func withDryRun(o *svcsdk.Options) {
+	// Clipped, so that clients made from the same options never share the
+	// appended option.
+	o.APIOptions = append(slices.Clip(o.APIOptions), func(stack *middleware.Stack) error {
+		// After, so that the input is validated as it would be otherwise.
+		return stack.Initialize.Add(dryRunMiddleware{}, middleware.After)
+	})
+}
+
+// dryRunMiddleware records the mutating ACM calls made with a context
+// holding a dryRunPlan into the plan and returns a made up output instead
+// of sending them. Other calls are sent as usual.
+type dryRunMiddleware struct{}
+
+// ID returns the identifier of the middleware.
+func (dryRunMiddleware) ID() string {
+	return "ACKDryRun"
+}
+
+// HandleInitialize implements middleware.InitializeMiddleware.
+func (dryRunMiddleware) HandleInitialize(
+	ctx context.Context,
+	in middleware.InitializeInput,
+	next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+	plan := dryRunPlanFromContext(ctx)
+	if plan == nil {
+		return next.HandleInitialize(ctx, in)
+	}
+	arn := fmt.Sprintf("arn:aws:acm:%s:%s:certificate/dry-run", awsmiddleware.GetRegion(ctx), plan.accountID)
+	var output interface{}
+	switch in.Parameters.(type) {
+	case *svcsdk.RequestCertificateInput:
+		output = &svcsdk.RequestCertificateOutput{CertificateArn: aws.String(arn)}
+	case *svcsdk.ImportCertificateInput:
+		output = &svcsdk.ImportCertificateOutput{CertificateArn: aws.String(arn)}
+	case *svcsdk.UpdateCertificateOptionsInput:
+		output = &svcsdk.UpdateCertificateOptionsOutput{}
+	case *svcsdk.AddTagsToCertificateInput:
+		output = &svcsdk.AddTagsToCertificateOutput{}
+	case *svcsdk.RemoveTagsFromCertificateInput:
+		output = &svcsdk.RemoveTagsFromCertificateOutput{}
+	case *svcsdk.ExportCertificateInput:
+		output = &svcsdk.ExportCertificateOutput{}
+	case *svcsdk.DeleteCertificateInput:
+		output = &svcsdk.DeleteCertificateOutput{}
+	case *svcsdk.RenewCertificateInput:
+		output = &svcsdk.RenewCertificateOutput{}
+	default:
+		return next.HandleInitialize(ctx, in)
+	}
+	plan.add("%s in %s %s", awsmiddleware.GetOperationName(ctx), awsmiddleware.GetRegion(ctx), redactedInput(in.Parameters))
+	return middleware.InitializeOutput{Result: output}, middleware.Metadata{}, nil
+}
+
+// redactedInput returns the JSON encoding of the supplied ACM input without
+// the fields in dryRunRedactedFields.
+func redactedInput(input interface{}) string {
+	b, err := json.Marshal(input)
+	if err != nil {
+		return reflect.TypeOf(input).String()
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return string(b)
+	}
+	for _, field := range dryRunRedactedFields {
+		if _, ok := fields[field]; ok {
+			fields[field] = "<redacted>"
+		}
+	}
+	pruneEmptyFields(fields)
+	b, err = json.Marshal(fields)
+	if err != nil {
+		return reflect.TypeOf(input).String()
+	}
+	return string(b)
+}
+
+// pruneEmptyFields removes the null and empty string fields of the supplied
+// decoded JSON object and of the objects nested in it, which stand for
+// fields of the ACM input that are not set.
+func pruneEmptyFields(fields map[string]interface{}) {
+	for field, value := range fields {
+		switch v := value.(type) {
+		case nil:
+			delete(fields, field)
+		case string:
+			if v == "" {
+				delete(fields, field)
+			}
+		case map[string]interface{}:
+			pruneEmptyFields(v)
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneEmptyFields(m)
+				}
+			}
+		}
+	}
+}
+
+// planDryRun adds the supplied change to the plan of the supplied context
+// and returns true if it is in dry-run mode, in which case the caller must
+// not make the change.
+func planDryRun(ctx context.Context, format string, args ...interface{}) bool {
+	plan := dryRunPlanFromContext(ctx)
+	if plan == nil {
+		return false
+	}
+	plan.add(format, args...)
+	return true
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// hasDryRunMiddleware returns true if the supplied API options add the
+// dry-run middleware to a stack.
+func hasDryRunMiddleware(t *testing.T, apiOptions []func(*middleware.Stack) error) bool {
+	t.Helper()
+	stack := middleware.NewStack("test", smithyhttp.NewStackRequest)
+	for _, fn := range apiOptions {
+		if err := fn(stack); err != nil {
+			t.Fatalf("applying API option: %v", err)
+		}
+	}
+	_, ok := stack.Initialize.Get(dryRunMiddleware{}.ID())
+	return ok
+}
+
+func TestPlannerClient(t *testing.T) {
+	rm, _, _ := newTestResourceManager(t)
+
+	if !hasDryRunMiddleware(t, rm.planner().sdkapi.Options().APIOptions) {
+		t.Errorf("the ACM client of the planner has no %s middleware", dryRunMiddleware{}.ID())
+	}
+	if hasDryRunMiddleware(t, rm.sdkapi.Options().APIOptions) {
+		t.Errorf("the ACM client of the resource manager has the %s middleware", dryRunMiddleware{}.ID())
+	}
+}
+
+func TestPlanCreate(t *testing.T) {
+	rm, _, _ := newTestResourceManager(t)
+
+	planned, err := rm.planCreate(context.Background(), newTestCertificate(svcapitypes.CertificateSpec{
+		DomainName: aws.String("www.example.org"),
+	}))
+	if err == nil || isTerminal(err) {
+		t.Fatalf("planCreate error = %v, want a requeue", err)
+	}
+	if arn := certificateARN(planned); arn != "" {
+		t.Errorf("planned certificate has ARN %s, want none", arn)
+	}
+	steps := aws.ToStringSlice(planned.ko.Status.DryRunPlan)
+	if len(steps) == 0 || !strings.HasPrefix(steps[0], "RequestCertificate") {
+		// Without the middleware the certificate is requested and nothing
+		// is planned.
+		t.Fatalf("DryRunPlan = %q, want a RequestCertificate step first", steps)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// eventComponent is the source component of the Events the controller
+// records.
+const eventComponent = "acm-controller"
+
+// recordEvent records an Event about the supplied Certificate. Events are
+// informational only, so a resource manager without a recorder records
+// none.
+func (rm *resourceManager) recordEvent(
+	ko *svcapitypes.Certificate,
+	eventType string,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	recorder := rm.kube().recorder
+	if recorder == nil {
+		return
+	}
+	recorder.Eventf(ko, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,580 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;patch;watch
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=list;watch
+
+const (
+	// gatewayAPIGroup is the API group of the Gateway API.
+	gatewayAPIGroup = "gateway.networking.k8s.io"
+
+	flagGatewayCertificateARNOption = "gateway-certificate-arn-option"
+
+	// DefaultGatewayCertificateARNOption is the default TLS option of a
+	// Gateway listener that the controller sets to the ARN of the
+	// Certificate the listener's tls.certificateRefs refer to. It is the
+	// option the AWS Gateway API Controller for VPC Lattice reads.
+	DefaultGatewayCertificateARNOption = "application-networking.k8s.aws/certificate-arn"
+
+	// gatewayCertificateRefIndex is the name of the index of Gateways by the
+	// namespace/name of the Certificates their listeners refer to.
+	gatewayCertificateRefIndex = "gatewayCertificateRefs"
+
+	gatewayReasonResolvedRefs    = "ResolvedRefs"
+	gatewayReasonRefNotPermitted = "RefNotPermitted"
+	gatewayReasonPending         = "Pending"
+	gatewayReasonRefRemoved      = "RefRemoved"
+)
+
+// gatewayCertificateARNOption is the TLS option of a Gateway listener that
+// the controller sets to the ARN of the Certificate, set with the
+// --gateway-certificate-arn-option flag for Gateway implementations that
+// read another option.
+var gatewayCertificateARNOption = DefaultGatewayCertificateARNOption
+
+func init() {
+	flag.StringVar(
+		&gatewayCertificateARNOption, flagGatewayCertificateARNOption,
+		DefaultGatewayCertificateARNOption,
+		"TLS option of Gateway API listeners that the controller sets to the "+
+			"ARN of the Certificate the listener's tls.certificateRefs refer to.",
+	)
+}
+
+var (
+	gatewayListGVK        = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "GatewayList"}
+	gatewayGVK            = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1", Kind: "Gateway"}
+	referenceGrantListGVK = schema.GroupVersionKind{Group: gatewayAPIGroup, Version: "v1beta1", Kind: "ReferenceGrantList"}
+)
+
+// findGatewayListener returns the entry of the supplied list for the
+// listener with the supplied name of the supplied Gateway, or nil.
+func findGatewayListener(
+	listeners []*svcapitypes.CertificateGatewayListener,
+	namespace string,
+	gateway string,
+	listener string,
+) *svcapitypes.CertificateGatewayListener {
+	for _, l := range listeners {
+		if l.Namespace == namespace && l.Gateway == gateway && l.Listener == listener {
+			return l
+		}
+	}
+	return nil
+}
+
+// issuedCertificateARN returns the ARN of the certificate observed in the
+// supplied object if it is issued, and an empty string otherwise.
+func issuedCertificateARN(ko *svcapitypes.Certificate) string {
+	if ko.Status.Status == nil ||
+		*ko.Status.Status != string(svcapitypes.CertificateStatus_SDK_ISSUED) ||
+		ko.Status.ACKResourceMetadata == nil ||
+		ko.Status.ACKResourceMetadata.ARN == nil {
+		return ""
+	}
+	return string(*ko.Status.ACKResourceMetadata.ARN)
+}
+
+// readGatewayListeners sets Status.GatewayListeners of the supplied object
+// to the Gateway listeners whose tls.certificateRefs refer to the
+// Certificate, along with whether the reference resolves. Listeners that
+// were recorded with the ARN of the certificate but no longer refer to it
+// are kept until the ARN is removed. If the Gateway API was not installed
+// when the controller started the list is cleared, and errors are only
+// logged: Gateways being unavailable must not prevent the certificate from
+// being read.
+func (rm *resourceManager) readGatewayListeners(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) {
+	rlog := ackrtlog.FromContext(ctx)
+	if !rm.kube().gatewaysIndexed {
+		ko.Status.GatewayListeners = nil
+		return
+	}
+	listeners, err := rm.listGatewayListeners(ctx, ko)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			ko.Status.GatewayListeners = nil
+			return
+		}
+		rlog.Info("unable to read gateway listeners", "error", err)
+		return
+	}
+	if len(listeners) == 0 {
+		listeners = nil
+	}
+	ko.Status.GatewayListeners = listeners
+}
+
+// unresolvedGatewayListeners returns true if a Gateway listener observed in
+// the supplied object refers to the Certificate without the reference
+// resolving. Nothing watches Gateways or ReferenceGrants, so the
+// Certificate is synced as often as a pending one until the reference
+// resolves or goes away.
+func unresolvedGatewayListeners(ko *svcapitypes.Certificate) bool {
+	return slices.ContainsFunc(ko.Status.GatewayListeners, func(l *svcapitypes.CertificateGatewayListener) bool {
+		return l.ResolvedRefs != "True"
+	})
+}
+
+// listGatewayListeners returns the status of the Gateway listeners that
+// refer to the supplied Certificate, or that carry an ARN recorded for them
+// in its Status.GatewayListeners.
+func (rm *resourceManager) listGatewayListeners(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) ([]*svcapitypes.CertificateGatewayListener, error) {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	gateways, err := listCertificateGateways(ctx, kc, ko)
+	if err != nil {
+		return nil, err
+	}
+	arn := issuedCertificateARN(ko)
+	var grants *unstructured.UnstructuredList
+	listeners := []*svcapitypes.CertificateGatewayListener{}
+	for _, gw := range gateways {
+		specListeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+		for _, item := range specListeners {
+			listener, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(listener, "name")
+			option, _, _ := unstructured.NestedString(listener, "tls", "options", gatewayCertificateARNOption)
+			status := &svcapitypes.CertificateGatewayListener{
+				Namespace:      gw.GetNamespace(),
+				Gateway:        gw.GetName(),
+				Listener:       name,
+				ResolvedRefs:   "False",
+				CertificateARN: option,
+			}
+			if !listenerRefersTo(listener, gw.GetNamespace(), ko) {
+				previous := findGatewayListener(ko.Status.GatewayListeners, gw.GetNamespace(), gw.GetName(), name)
+				if option == "" || previous == nil || previous.CertificateARN != option {
+					continue
+				}
+				status.Reason = gatewayReasonRefRemoved
+				status.Message = "The listener no longer refers to the Certificate"
+				listeners = append(listeners, status)
+				continue
+			}
+			permitted := gw.GetNamespace() == ko.Namespace
+			if !permitted {
+				if grants == nil {
+					grants = &unstructured.UnstructuredList{}
+					grants.SetGroupVersionKind(referenceGrantListGVK)
+					if err := kc.List(ctx, grants, client.InNamespace(ko.Namespace)); err != nil && !meta.IsNoMatchError(err) {
+						return nil, err
+					}
+				}
+				permitted = referenceGranted(grants.Items, gw.GetNamespace(), ko.Name)
+			}
+			switch {
+			case !permitted:
+				status.Reason = gatewayReasonRefNotPermitted
+				status.Message = fmt.Sprintf(
+					"No ReferenceGrant in namespace %s permits Gateways in namespace %s to refer to the Certificate",
+					ko.Namespace, gw.GetNamespace(),
+				)
+			case arn == "":
+				status.Reason = gatewayReasonPending
+				status.Message = "The certificate is not issued"
+			default:
+				status.ResolvedRefs = "True"
+				status.Reason = gatewayReasonResolvedRefs
+			}
+			listeners = append(listeners, status)
+		}
+	}
+	return listeners, nil
+}
+
+// listCertificateGateways returns the Gateways with a listener that refers
+// to the supplied Certificate, read with the gatewayCertificateRefIndex, and
+// the Gateways of the listeners recorded with an ARN in its
+// Status.GatewayListeners, sorted by namespace and name.
+func listCertificateGateways(
+	ctx context.Context,
+	kc client.Reader,
+	ko *svcapitypes.Certificate,
+) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gatewayListGVK)
+	key := types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name}.String()
+	if err := kc.List(ctx, list, client.MatchingFields{gatewayCertificateRefIndex: key}); err != nil {
+		return nil, err
+	}
+	gateways := list.Items
+	for _, l := range ko.Status.GatewayListeners {
+		if l.CertificateARN == "" || slices.ContainsFunc(gateways, func(gw unstructured.Unstructured) bool {
+			return gw.GetNamespace() == l.Namespace && gw.GetName() == l.Gateway
+		}) {
+			continue
+		}
+		gw := unstructured.Unstructured{}
+		gw.SetGroupVersionKind(gatewayGVK)
+		nn := types.NamespacedName{Namespace: l.Namespace, Name: l.Gateway}
+		if err := kc.Get(ctx, nn, &gw); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading Gateway %s: %w", nn, err)
+		}
+		gateways = append(gateways, gw)
+	}
+	slices.SortFunc(gateways, func(a, b unstructured.Unstructured) int {
+		if c := strings.Compare(a.GetNamespace(), b.GetNamespace()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	return gateways, nil
+}
+
+// indexGatewayCertificateRefs adds the gatewayCertificateRefIndex to the
+// supplied indexer. It returns false if the Gateway API is not installed.
+func indexGatewayCertificateRefs(
+	ctx context.Context,
+	indexer client.FieldIndexer,
+) (bool, error) {
+	gw := &unstructured.Unstructured{}
+	gw.SetGroupVersionKind(gatewayGVK)
+	err := indexer.IndexField(ctx, gw, gatewayCertificateRefIndex, gatewayCertificateRefs)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// gatewayCertificateRefs returns the namespace/name of the Certificates the
+// listeners of the supplied Gateway refer to.
+func gatewayCertificateRefs(obj client.Object) []string {
+	gw, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil
+	}
+	keys := []string{}
+	specListeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	for _, item := range specListeners {
+		listener, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, ref := range listenerCertificateRefs(listener, gw.GetNamespace()) {
+			if key := ref.String(); !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// listenerRefersTo returns true if the tls.certificateRefs of the supplied
+// listener of a Gateway in namespace gatewayNamespace refer to the supplied
+// Certificate.
+func listenerRefersTo(
+	listener map[string]interface{},
+	gatewayNamespace string,
+	ko *svcapitypes.Certificate,
+) bool {
+	return slices.Contains(
+		listenerCertificateRefs(listener, gatewayNamespace),
+		types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name},
+	)
+}
+
+// listenerCertificateRefs returns the namespace and name of the
+// Certificates the tls.certificateRefs of the supplied listener of a
+// Gateway in namespace gatewayNamespace refer to.
+func listenerCertificateRefs(
+	listener map[string]interface{},
+	gatewayNamespace string,
+) []types.NamespacedName {
+	refs, _, _ := unstructured.NestedSlice(listener, "tls", "certificateRefs")
+	certificates := []types.NamespacedName{}
+	for _, item := range refs {
+		ref, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(ref, "group")
+		kind, _, _ := unstructured.NestedString(ref, "kind")
+		name, _, _ := unstructured.NestedString(ref, "name")
+		namespace, _, _ := unstructured.NestedString(ref, "namespace")
+		if namespace == "" {
+			namespace = gatewayNamespace
+		}
+		if group == svcapitypes.GroupVersion.Group && kind == "Certificate" {
+			certificates = append(certificates, types.NamespacedName{Namespace: namespace, Name: name})
+		}
+	}
+	return certificates
+}
+
+// referenceGranted returns true if one of the supplied ReferenceGrants
+// permits Gateways in namespace from to refer to the Certificate with the
+// supplied name.
+func referenceGranted(
+	grants []unstructured.Unstructured,
+	from string,
+	name string,
+) bool {
+	for _, grant := range grants {
+		froms, _, _ := unstructured.NestedSlice(grant.Object, "spec", "from")
+		tos, _, _ := unstructured.NestedSlice(grant.Object, "spec", "to")
+		fromOK := false
+		for _, item := range froms {
+			f, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			group, _, _ := unstructured.NestedString(f, "group")
+			kind, _, _ := unstructured.NestedString(f, "kind")
+			namespace, _, _ := unstructured.NestedString(f, "namespace")
+			if group == gatewayAPIGroup && kind == "Gateway" && namespace == from {
+				fromOK = true
+				break
+			}
+		}
+		if !fromOK {
+			continue
+		}
+		for _, item := range tos {
+			t, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			group, _, _ := unstructured.NestedString(t, "group")
+			kind, _, _ := unstructured.NestedString(t, "kind")
+			toName, _, _ := unstructured.NestedString(t, "name")
+			if group == svcapitypes.GroupVersion.Group && kind == "Certificate" &&
+				(toName == "" || toName == name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wantedGatewayListeners returns the Gateway listeners observed in the
+// supplied object with the ARN their TLS option should have: the ARN of the
+// issued certificate for the listeners whose reference resolves, and none
+// for the others.
+func wantedGatewayListeners(
+	ko *svcapitypes.Certificate,
+) []*svcapitypes.CertificateGatewayListener {
+	arn := issuedCertificateARN(ko)
+	wanted := make([]*svcapitypes.CertificateGatewayListener, 0, len(ko.Status.GatewayListeners))
+	for _, l := range ko.Status.GatewayListeners {
+		w := l.DeepCopy()
+		w.CertificateARN = ""
+		if l.ResolvedRefs == "True" {
+			w.CertificateARN = arn
+		}
+		wanted = append(wanted, w)
+	}
+	return wanted
+}
+
+// compareGatewayListeners forces an update while the TLS option of a
+// Gateway listener does not have the ARN of the certificate its reference
+// resolves to, or still has it once the reference no longer resolves, so
+// that sdkUpdate gets a chance to patch the Gateway.
+func compareGatewayListeners(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	observed := b.ko.Status.GatewayListeners
+	if len(observed) == 0 {
+		return
+	}
+	wanted := wantedGatewayListeners(b.ko)
+	// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+	// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+	if !equality.Semantic.DeepEqual(wanted, observed) {
+		delta.Add("Spec.Status.GatewayListeners", wanted, observed)
+	}
+}
+
+// syncGatewayListeners sets the TLS option of the Gateway listeners in
+// Status.GatewayListeners of latest to the ARN their reference resolves to,
+// or removes it. The returned resource records the ARN of each listener,
+// including when an error is returned, in which case the listeners that
+// could not be patched are retried after a delay.
+func (rm *resourceManager) syncGatewayListeners(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncGatewayListeners")
+	defer func() { exit(err) }()
+
+	ko := desired.ko.DeepCopy()
+	ko.Status.GatewayListeners = latest.ko.Status.GatewayListeners
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return &resource{ko}, err
+	}
+	listeners := []*svcapitypes.CertificateGatewayListener{}
+	var errs []error
+	for _, w := range wantedGatewayListeners(latest.ko) {
+		observed := findGatewayListener(latest.ko.Status.GatewayListeners, w.Namespace, w.Gateway, w.Listener)
+		if observed.CertificateARN == w.CertificateARN {
+			listeners = append(listeners, w)
+			continue
+		}
+		if err := patchGatewayListenerOption(ctx, kc, w.Namespace, w.Gateway, w.Listener, w.CertificateARN, observed.CertificateARN); err != nil {
+			errs = append(errs, err)
+			listeners = append(listeners, observed)
+			continue
+		}
+		rlog.Info("updated certificate ARN option of gateway listener",
+			"namespace", w.Namespace, "gateway", w.Gateway, "listener", w.Listener, "arn", w.CertificateARN)
+		if w.Reason == gatewayReasonRefRemoved {
+			continue
+		}
+		listeners = append(listeners, w)
+	}
+	if len(listeners) == 0 {
+		listeners = nil
+	}
+	ko.Status.GatewayListeners = listeners
+	if len(errs) > 0 {
+		return &resource{ko}, ackrequeue.NeededAfter(errors.Join(errs...), requeuePending)
+	}
+	return &resource{ko}, nil
+}
+
+// releaseGatewayListeners removes the ARN of a certificate that is being
+// deleted from the TLS option of the Gateway listeners it was set on, so
+// that the Gateway implementation stops using it and ACM lets it be
+// deleted.
+func (rm *resourceManager) releaseGatewayListeners(
+	ctx context.Context,
+	r *resource,
+) error {
+	var kc client.Client
+	var errs []error
+	for _, l := range r.ko.Status.GatewayListeners {
+		if l.CertificateARN == "" {
+			continue
+		}
+		if kc == nil {
+			var err error
+			if kc, err = rm.kubeClient(); err != nil {
+				return err
+			}
+		}
+		errs = append(errs, patchGatewayListenerOption(ctx, kc, l.Namespace, l.Gateway, l.Listener, "", l.CertificateARN))
+	}
+	return errors.Join(errs...)
+}
+
+// patchGatewayListenerOption sets the certificate ARN TLS option of the
+// supplied Gateway listener to value, or removes it if value is empty and
+// the option still has the ARN previous. A Gateway or listener that does
+// not exist is only an error when the option is set.
+func patchGatewayListenerOption(
+	ctx context.Context,
+	kc client.Client,
+	namespace string,
+	gateway string,
+	listener string,
+	value string,
+	previous string,
+) error {
+	gw := &unstructured.Unstructured{}
+	gw.SetGroupVersionKind(gatewayGVK)
+	nn := types.NamespacedName{Namespace: namespace, Name: gateway}
+	if err := kc.Get(ctx, nn, gw); err != nil {
+		if apierrors.IsNotFound(err) && value == "" {
+			return nil
+		}
+		return fmt.Errorf("reading Gateway %s: %w", nn, err)
+	}
+	// The optimistic lock makes the patch fail rather than overwrite
+	// listeners changed since the Gateway was read.
+	patch := client.MergeFromWithOptions(gw.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	specListeners, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+	for _, item := range specListeners {
+		l, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(l, "name"); name != listener {
+			continue
+		}
+		options, _, _ := unstructured.NestedStringMap(l, "tls", "options")
+		if options == nil {
+			options = map[string]string{}
+		}
+		current := options[gatewayCertificateARNOption]
+		if current == value || (value == "" && current != previous) {
+			return nil
+		}
+		if value == "" {
+			delete(options, gatewayCertificateARNOption)
+		} else {
+			options[gatewayCertificateARNOption] = value
+		}
+		if len(options) == 0 {
+			unstructured.RemoveNestedField(l, "tls", "options")
+		} else if err := unstructured.SetNestedStringMap(l, options, "tls", "options"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedSlice(gw.Object, specListeners, "spec", "listeners"); err != nil {
+			return err
+		}
+		if planDryRun(ctx, "set TLS option %s of listener %s of Gateway %s to %q", gatewayCertificateARNOption, listener, nn, value) {
+			return nil
+		}
+		if err := kc.Patch(ctx, gw, patch); err != nil {
+			return fmt.Errorf("patching Gateway %s: %w", nn, err)
+		}
+		return nil
+	}
+	if value == "" {
+		return nil
+	}
+	return fmt.Errorf("Gateway %s has no listener %q", nn, listener)
+}
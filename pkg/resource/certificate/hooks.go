@@ -15,59 +15,34 @@ package certificate
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
 	"errors"
-	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/aws-controllers-k8s/acm-controller/pkg/tags"
 	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
 	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
-	pkcs8 "github.com/youmark/pkcs8"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
 )
 
-const (
-	// DNS validation only works for up to 5 chained CNAME records
-	limitDomainValidationOptionsPublic = 5
-)
-
-var (
-	errTooManyDomainValidationOptions = errors.New(
-		"Too many domain validation errors",
-	)
-
-	domainValidationOptionsExceededMsg = fmt.Sprintf(
-		"Certificate cannot have more than %d domain validation options "+
-			"when requesting a public certificate",
-		limitDomainValidationOptionsPublic,
-	)
-)
-
-// validatePublicValidationOptions checks that when requesting a public
-// certificate, we do not exceed the number of additional CNAME records that
-// DNS verification can handle.
-func validatePublicValidationOptions(
+// validateCertificateRequest checks the fields of the desired resource that
+// are sent to ACM when requesting a certificate, including its tags, so that
+// requests ACM would reject, or certificates DNS validation cannot handle,
+// are reported as terminal errors instead of being retried.
+func validateCertificateRequest(
 	r *resource,
 ) error {
-	// If the certificateAuthorityARN field is empty, that means this is a
-	// request for a public certificate. If so, because we require DNS
-	// verification for public certificates (due to email verification not be
-	// automateable), we need to limit the number of chained CNAME records in
-	// the DomainValidationOptions field to 5, since DNS verification only
-	// works on up to 5 subdomains.
-	if r.ko.Spec.CertificateAuthorityARN != nil {
-		numDVOptions := len(r.ko.Spec.DomainValidationOptions)
-		if numDVOptions > limitDomainValidationOptionsPublic {
-			return errTooManyDomainValidationOptions
-		}
+	if errs := validateRequest(r.ko); len(errs) > 0 {
+		return ackerr.NewTerminalError(errs.ToAggregate())
+	}
+	if err := tags.Validate(r.ko.Spec.Tags); err != nil {
+		return ackerr.NewTerminalError(err)
 	}
 	return nil
 }
@@ -75,19 +50,50 @@ func validatePublicValidationOptions(
 // maybeImportCertificate imports a certificate into ACM if Spec.Certificate is set.
 func (rm *resourceManager) maybeImportCertificate(ctx context.Context, r *resource) (*resource, bool, error) {
 	certSpec := r.ko.Spec
-	if certSpec.Certificate != nil {
+	if certSpec.Certificate != nil || hasImportSource(r.ko) {
 		if certSpec.DomainName != nil || len(certSpec.DomainValidationOptions) > 0 || certSpec.KeyAlgorithm != nil ||
 			len(certSpec.SubjectAlternativeNames) > 0 || certSpec.Options != nil {
 			return nil, false, ackerr.NewTerminalError(errors.New("cannot set fields used for requesting a certificate when importing a certificate"))
 		}
+		if hasImportSource(r.ko) && (certSpec.Certificate != nil || certSpec.PrivateKey != nil || certSpec.CertificateChain != nil) {
+			return nil, false, ackerr.NewTerminalError(errors.New("cannot set certificate, privateKey or certificateChain when importing from a Secret"))
+		}
+		if certSpec.ImportFrom != nil && certSpec.CertManagerCertificateRef != nil {
+			return nil, false, ackerr.NewTerminalError(errors.New("cannot set both importFrom and certManagerCertificateRef"))
+		}
+		if err := tags.Validate(certSpec.Tags); err != nil {
+			return nil, false, ackerr.NewTerminalError(err)
+		}
 		input, err := rm.newImportCertificateInput(ctx, r)
 		if err != nil {
 			return nil, false, err
 		}
+		if input.Tags, err = rm.sdkTags(ctx, r); err != nil {
+			return nil, false, err
+		}
+		if hasImportSource(r.ko) {
+			if err := rm.setImportCertificateInputFromTLSSecret(ctx, r, input); err != nil {
+				return nil, false, err
+			}
+		}
 		if len(input.PrivateKey) == 0 {
 			return nil, false, ackerr.NewTerminalError(errors.New("privateKey is required when importing a certificate"))
 		}
-		created, err := rm.importCertificate(ctx, r, input)
+		if err := inspectImportCertificateInput(input, time.Now()); err != nil {
+			return nil, false, ackerr.NewTerminalError(err)
+		}
+		req, err := importedPolicyRequest(input)
+		if err != nil {
+			return nil, false, ackerr.NewTerminalError(err)
+		}
+		if err := rm.checkCertificatePolicies(ctx, r.ko.Namespace, req); err != nil {
+			return nil, false, err
+		}
+		approved, err := rm.awaitApproval(ctx, r, req)
+		if err != nil {
+			return approved, true, err
+		}
+		created, err := rm.importCertificate(ctx, approved, input)
 		if err != nil {
 			return nil, false, err
 		}
@@ -99,6 +105,54 @@ func (rm *resourceManager) maybeImportCertificate(ctx context.Context, r *resour
 	return nil, false, nil
 }
 
+// setRequestCertificateDefaults sets the RequestCertificate input fields that
+// are not exposed in the Certificate spec, and the tags including the
+// default tags.
+func (rm *resourceManager) setRequestCertificateDefaults(
+	ctx context.Context,
+	desired *resource,
+	input *svcsdk.RequestCertificateInput,
+) error {
+	// We only support DNS-based validation, because
+	// certificate renewal is not really automatable when email verification
+	// is used.
+	//
+	// See discussion here:
+	// https://docs.aws.amazon.com/acm/latest/userguide/email-validation.html
+	//
+	// Unfortunately, because fields in the "ignore" configuration list are
+	// now deleted from the aws-sdk-go private/model/api.Shape object,
+	// setting `override_values` does not work.
+	input.ValidationMethod = svcsdktypes.ValidationMethodDns
+
+	// KeyAlgorithm is late initialized from DescribeCertificate, which
+	// reports it as e.g. RSA-2048 rather than the RSA_2048 RequestCertificate
+	// accepts.
+	if input.KeyAlgorithm != "" {
+		input.KeyAlgorithm = svcsdktypes.KeyAlgorithm(normalizeKeyAlgorithm(string(input.KeyAlgorithm)))
+	}
+
+	// A public certificate can only be exported into ExportTo if it was
+	// requested exportable, so that is the default when ExportTo is set
+	// and Options.Export is not.
+	// NOTE: exportPreference can ONLY be set for public certificates
+	if desired.ko.Spec.ExportTo != nil && isPublicRequestSpec(desired.ko) {
+		if input.Options == nil {
+			input.Options = &svcsdktypes.CertificateOptions{}
+		}
+		if input.Options.Export == "" {
+			input.Options.Export = svcsdktypes.CertificateExportEnabled
+		}
+	}
+
+	tags, err := rm.sdkTags(ctx, desired)
+	if err != nil {
+		return err
+	}
+	input.Tags = tags
+	return nil
+}
+
 var (
 	syncTags = tags.SyncTags
 	listTags = tags.ListTags
@@ -162,11 +216,21 @@ func (rm *resourceManager) exportCertificate(
 	if r.ko.Spec.ExportTo == nil {
 		return nil
 	}
+	if err := rm.checkSecretReferenceGrant(ctx, r.ko, r.ko.Spec.ExportTo, SecretGrantAccessWrite); err != nil {
+		return err
+	}
 
 	input := &svcsdk.ExportCertificateInput{}
 	if r.ko.Status.ACKResourceMetadata != nil && r.ko.Status.ACKResourceMetadata.ARN != nil {
 		input.CertificateArn = (*string)(r.ko.Status.ACKResourceMetadata.ARN)
 	}
+	secretNamespace := r.ko.Spec.ExportTo.Namespace
+	if secretNamespace == "" {
+		secretNamespace = r.ko.Namespace
+	}
+	if planDryRun(ctx, "ExportCertificate %s into Secret %s/%s", aws.ToString(input.CertificateArn), secretNamespace, r.ko.Spec.ExportTo.Name) {
+		return nil
+	}
 
 	passphraseLength := 8 // Desired length of the passphrase
 	passphrase, err := generateRandomString(passphraseLength)
@@ -181,9 +245,12 @@ func (rm *resourceManager) exportCertificate(
 		return err
 	}
 
+	if resp.Certificate == nil || resp.PrivateKey == nil {
+		return errors.New("ExportCertificate returned no certificate or private key")
+	}
 	certificateChain := *resp.Certificate
-	if resp.CertificateChain != nil && *resp.CertificateChain != "" {
-		certificateChain = certificateChain + *resp.CertificateChain
+	if resp.CertificateChain != nil {
+		certificateChain = joinPEM(certificateChain, *resp.CertificateChain)
 	}
 
 	if r.ko.Spec.ExportTo.Namespace != "" {
@@ -196,9 +263,13 @@ func (rm *resourceManager) exportCertificate(
 		}
 	}
 
-	decryptedKey, err := DecryptPrivateKey([]byte(*resp.PrivateKey), []byte(passphrase), *r.ko.Spec.KeyAlgorithm)
+	keyFormat := PrivateKeyFormatPKCS8
+	if r.ko.Spec.ExportKeyFormat != nil {
+		keyFormat = *r.ko.Spec.ExportKeyFormat
+	}
+	decryptedKey, err := DecryptPrivateKey([]byte(*resp.PrivateKey), []byte(passphrase), keyFormat)
 	if err != nil {
-		return err
+		return ackerr.NewTerminalError(err)
 	}
 
 	if r.ko.Spec.ExportTo.Namespace != "" {
@@ -216,42 +287,6 @@ func (rm *resourceManager) exportCertificate(
 	return nil
 }
 
-func DecryptPrivateKey(encryptedPEM, passphrase []byte, keyAlgorithm string) ([]byte, error) {
-	pemBlock, _ := pem.Decode(encryptedPEM)
-	if pemBlock == nil {
-		return nil, errors.New("failed to decode PEM block: no PEM data found")
-	}
-	privateKey, err := pkcs8.ParsePKCS8PrivateKey(pemBlock.Bytes, passphrase)
-	if err != nil {
-		return nil, errors.New("failed to decrypt PEM block")
-	}
-
-	// NOTE: Algorithms supported for an ACM certificate request include: RSA_2048, EC_prime256v1, EC_secp384r1
-	if strings.Contains(keyAlgorithm, "RSA") {
-		derBytes, err := x509.MarshalPKCS8PrivateKey(privateKey.(*rsa.PrivateKey))
-		if err != nil {
-			return nil, errors.New("failed to marshal PEM block")
-		}
-
-		pemBytes := pem.EncodeToMemory(&pem.Block{
-			Type:  "PRIVATE KEY",
-			Bytes: derBytes,
-		})
-		return pemBytes, err
-	} else {
-		derBytes, err := x509.MarshalPKCS8PrivateKey(privateKey.(*ecdsa.PrivateKey))
-		if err != nil {
-			return nil, errors.New("failed to marshal PEM block")
-		}
-
-		pemBytes := pem.EncodeToMemory(&pem.Block{
-			Type:  "PRIVATE KEY",
-			Bytes: derBytes,
-		})
-		return pemBytes, err
-	}
-}
-
 // normalizeKeyAlgorithm normalizes a KeyAlgorithm value by replacing all dash
 // characters with underscore characters. This ensures consistency between the
 // user-specified format (e.g., RSA_2048) and the AWS API response format
@@ -274,6 +309,50 @@ func compareKeyAlgorithm(
 	}
 }
 
+// subjectAlternativeNameSet returns the set of names covered by the supplied
+// resource's DomainName and SubjectAlternativeNames. ACM always includes the
+// DomainName as the first SAN of a certificate, so a manifest that omits it
+// from SubjectAlternativeNames describes the same certificate as one that
+// lists it explicitly. Names are compared case-insensitively, as DNS names
+// are.
+func subjectAlternativeNameSet(r *resource) map[string]struct{} {
+	names := map[string]struct{}{}
+	if r.ko.Spec.DomainName != nil {
+		names[strings.ToLower(*r.ko.Spec.DomainName)] = struct{}{}
+	}
+	for _, san := range r.ko.Spec.SubjectAlternativeNames {
+		if san != nil {
+			names[strings.ToLower(*san)] = struct{}{}
+		}
+	}
+	return names
+}
+
+// compareSubjectAlternativeNames compares the SubjectAlternativeNames of two
+// resources as sets, ignoring ordering and the implicit inclusion of the
+// DomainName that DescribeCertificate reports.
+func compareSubjectAlternativeNames(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	if len(a.ko.Spec.SubjectAlternativeNames) == 0 && len(b.ko.Spec.SubjectAlternativeNames) == 0 {
+		return
+	}
+	namesA := subjectAlternativeNameSet(a)
+	namesB := subjectAlternativeNameSet(b)
+	if len(namesA) != len(namesB) {
+		delta.Add("Spec.SubjectAlternativeNames", a.ko.Spec.SubjectAlternativeNames, b.ko.Spec.SubjectAlternativeNames)
+		return
+	}
+	for name := range namesA {
+		if _, found := namesB[name]; !found {
+			delta.Add("Spec.SubjectAlternativeNames", a.ko.Spec.SubjectAlternativeNames, b.ko.Spec.SubjectAlternativeNames)
+			return
+		}
+	}
+}
+
 func compareCertificateIssuedAt(
 	delta *ackcompare.Delta,
 	a *resource,
@@ -0,0 +1,162 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlrtcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlrtlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// importSourceControllerName is the name of the controller that syncs
+	// the Certificates importing a Secret when the Secret changes.
+	importSourceControllerName = "certificate-import-source"
+
+	// importFromIndex is the name of the index of Certificates by the
+	// namespace/name of the Secret their ImportFrom refers to.
+	importFromIndex = "importFrom"
+	// certManagerCertificateRefIndex is the name of the index of
+	// Certificates by the namespace/name of the cert-manager Certificate
+	// their CertManagerCertificateRef refers to.
+	certManagerCertificateRefIndex = "certManagerCertificateRef"
+
+	// certManagerCertificateNameAnnotation is the annotation cert-manager
+	// sets on the Secrets it writes to the name of their cert-manager
+	// Certificate.
+	certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+)
+
+// indexImportSources adds the importFromIndex and the
+// certManagerCertificateRefIndex to the supplied indexer.
+func indexImportSources(
+	ctx context.Context,
+	indexer client.FieldIndexer,
+) error {
+	if err := indexer.IndexField(ctx, &svcapitypes.Certificate{}, importFromIndex, importFromKeys); err != nil {
+		return err
+	}
+	return indexer.IndexField(ctx, &svcapitypes.Certificate{}, certManagerCertificateRefIndex, certManagerCertificateRefKeys)
+}
+
+// importFromKeys returns the namespace/name of the Secret the ImportFrom of
+// the supplied Certificate refers to.
+func importFromKeys(obj client.Object) []string {
+	ko, ok := obj.(*svcapitypes.Certificate)
+	if !ok || ko.Spec.ImportFrom == nil {
+		return nil
+	}
+	return []string{importFromSecretName(ko).String()}
+}
+
+// certManagerCertificateRefKeys returns the namespace/name of the
+// cert-manager Certificate the CertManagerCertificateRef of the supplied
+// Certificate refers to.
+func certManagerCertificateRefKeys(obj client.Object) []string {
+	ko, ok := obj.(*svcapitypes.Certificate)
+	if !ok || ko.Spec.CertManagerCertificateRef == nil {
+		return nil
+	}
+	return []string{certManagerCertificateName(ko.Namespace, ko.Spec.CertManagerCertificateRef).String()}
+}
+
+// watchImportSources adds a controller to the supplied controller manager
+// that syncs the Certificates importing a Secret, directly or through a
+// cert-manager Certificate, as soon as the Secret is created or changes,
+// rather than on their next requeue. Secrets are watched with the
+// supplied cache, which holds their metadata only, and Certificates are
+// read with the supplied reader. Secrets outside the namespaces the cache
+// holds are only read again when the Certificate is requeued.
+//
+// The ACK runtime does not let the Certificate controller watch other
+// objects, so the Certificates are synced with its reconciler from the
+// import source controller. The two controllers may sync a Certificate at
+// the same time, which only repeats the same calls.
+func watchImportSources(
+	mgr ctrlrt.Manager,
+	cache ctrlrtcache.Cache,
+	reader client.Reader,
+) error {
+	started := time.Now()
+	secret := &metav1.PartialObjectMetadata{}
+	secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return ctrlrt.NewControllerManagedBy(mgr).
+		Named(importSourceControllerName).
+		WatchesRawSource(source.Kind(
+			cache,
+			secret,
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *metav1.PartialObjectMetadata) []reconcile.Request {
+				return certificatesImporting(ctx, cache, secret)
+			}),
+			predicate.TypedFuncs[*metav1.PartialObjectMetadata]{
+				// The Secrets that exist when the controller starts are read
+				// by the initial sync of the Certificates.
+				CreateFunc: func(e event.TypedCreateEvent[*metav1.PartialObjectMetadata]) bool {
+					return e.Object.GetCreationTimestamp().After(started)
+				},
+				DeleteFunc: func(event.TypedDeleteEvent[*metav1.PartialObjectMetadata]) bool {
+					return false
+				},
+				GenericFunc: func(event.TypedGenericEvent[*metav1.PartialObjectMetadata]) bool {
+					return false
+				},
+			},
+		)).
+		Complete(&certificateReconciler{reader: reader})
+}
+
+// certificatesImporting returns a request for each Certificate that imports
+// the supplied Secret, either through ImportFrom or through the cert-manager
+// Certificate named by its cert-manager.io/certificate-name annotation.
+func certificatesImporting(
+	ctx context.Context,
+	reader client.Reader,
+	secret client.Object,
+) []reconcile.Request {
+	lookups := map[string]string{
+		importFromIndex: client.ObjectKeyFromObject(secret).String(),
+	}
+	if name := secret.GetAnnotations()[certManagerCertificateNameAnnotation]; name != "" {
+		lookups[certManagerCertificateRefIndex] = types.NamespacedName{Namespace: secret.GetNamespace(), Name: name}.String()
+	}
+	var requests []reconcile.Request
+	for index, key := range lookups {
+		list := &svcapitypes.CertificateList{}
+		if err := reader.List(ctx, list, client.MatchingFields{index: key}); err != nil {
+			ctrlrtlog.FromContext(ctx).Error(err, "unable to list certificates importing secret",
+				"namespace", secret.GetNamespace(), "name", secret.GetName())
+			continue
+		}
+		for i := range list.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&list.Items[i]),
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,168 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestCertificatesImporting(t *testing.T) {
+	importing := func(name string, spec svcapitypes.CertificateSpec) *svcapitypes.Certificate {
+		return &svcapitypes.Certificate{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Spec:       spec,
+		}
+	}
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	kc := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&svcapitypes.Certificate{}, importFromIndex, importFromKeys).
+		WithIndex(&svcapitypes.Certificate{}, certManagerCertificateRefIndex, certManagerCertificateRefKeys).
+		WithObjects(
+			importing("import-from", svcapitypes.CertificateSpec{
+				ImportFrom: &svcapitypes.TLSSecretReference{Name: "tls"},
+			}),
+			importing("import-from-other-namespace", svcapitypes.CertificateSpec{
+				ImportFrom: &svcapitypes.TLSSecretReference{Name: "tls", Namespace: "other"},
+			}),
+			importing("cert-manager", svcapitypes.CertificateSpec{
+				CertManagerCertificateRef: &svcapitypes.CertManagerCertificateReference{Name: "web"},
+			}),
+			importing("requested", svcapitypes.CertificateSpec{DomainName: aws.String("www.example.com")}),
+		).
+		Build()
+
+	tests := []struct {
+		name   string
+		secret metav1.ObjectMeta
+		want   []string
+	}{
+		{
+			name:   "import from",
+			secret: metav1.ObjectMeta{Name: "tls", Namespace: testNamespace},
+			want:   []string{"import-from"},
+		},
+		{
+			name:   "import from another namespace",
+			secret: metav1.ObjectMeta{Name: "tls", Namespace: "other"},
+			want:   []string{"import-from-other-namespace"},
+		},
+		{
+			name: "written by cert-manager",
+			secret: metav1.ObjectMeta{
+				Name:        "web-tls",
+				Namespace:   testNamespace,
+				Annotations: map[string]string{certManagerCertificateNameAnnotation: "web"},
+			},
+			want: []string{"cert-manager"},
+		},
+		{
+			name:   "not imported",
+			secret: metav1.ObjectMeta{Name: "unrelated", Namespace: testNamespace},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &metav1.PartialObjectMetadata{ObjectMeta: tt.secret}
+			var got []string
+			for _, req := range certificatesImporting(context.Background(), kc, secret) {
+				if req.Namespace != testNamespace {
+					t.Errorf("request for %s, want namespace %s", req.NamespacedName, testNamespace)
+				}
+				got = append(got, req.Name)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("certificatesImporting() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadImportSourceSerial(t *testing.T) {
+	certPEM, keyPEM := selfSignedCertificate(t, "imported.example.org", time.Now().Add(24*time.Hour))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: testNamespace},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte(certPEM), corev1.TLSPrivateKeyKey: []byte(keyPEM)},
+	}
+	kc := newFakeKubeClient(t, secret)
+	bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+	stored := &corev1.Secret{}
+	if err := kc.Get(context.Background(), client.ObjectKeyFromObject(secret), stored); err != nil {
+		t.Fatal(err)
+	}
+	certs, err := parseCertificates([]byte(certPEM))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial := formatSerial(certs[0].SerialNumber)
+
+	tests := []struct {
+		name            string
+		serial          *string
+		resourceVersion *string
+		want            string
+	}{
+		{name: "not read yet", want: serial},
+		{
+			name:            "Secret unchanged",
+			serial:          aws.String("recorded"),
+			resourceVersion: aws.String(stored.ResourceVersion),
+			want:            "recorded",
+		},
+		{
+			name:            "Secret changed",
+			serial:          aws.String("recorded"),
+			resourceVersion: aws.String(stored.ResourceVersion + "0"),
+			want:            serial,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, _, _ := newTestResourceManager(t)
+			bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+			r := newTestCertificate(svcapitypes.CertificateSpec{
+				ImportFrom: &svcapitypes.TLSSecretReference{Name: "tls"},
+			})
+			r.ko.Status.ImportSourceSerial = tt.serial
+			r.ko.Status.ImportSourceResourceVersion = tt.resourceVersion
+			rm.setImportSourceSerial(context.Background(), r)
+			if got := aws.ToString(r.ko.Status.ImportSourceSerial); got != tt.want {
+				t.Errorf("Status.ImportSourceSerial = %s, want %s", got, tt.want)
+			}
+			if got := aws.ToString(r.ko.Status.ImportSourceResourceVersion); got != stored.ResourceVersion {
+				t.Errorf("Status.ImportSourceResourceVersion = %s, want %s", got, stored.ResourceVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+)
+
+const (
+	pemTypeCertificate         = "CERTIFICATE"
+	pemTypePrivateKey          = "PRIVATE KEY"
+	pemTypeRSAPrivateKey       = "RSA PRIVATE KEY"
+	pemTypeECPrivateKey        = "EC PRIVATE KEY"
+	pemTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+
+	// notBeforeClockSkew is how far in the future the start of the validity
+	// period of a certificate may be, to allow for clock differences with
+	// the issuer of certificates that are imported right after issuance.
+	notBeforeClockSkew = 5 * time.Minute
+)
+
+var (
+	// acmRSAKeySizes are the RSA key sizes, in bits, of the certificates
+	// ACM can import and export.
+	acmRSAKeySizes = map[int]bool{1024: true, 2048: true, 3072: true, 4096: true}
+	// acmECCurves are the elliptic curves of the certificates ACM can
+	// import and export.
+	acmECCurves = map[elliptic.Curve]bool{
+		elliptic.P256(): true,
+		elliptic.P384(): true,
+		elliptic.P521(): true,
+	}
+)
+
+// inspectImportCertificateInput parses the PEM encoded certificate, private
+// key and certificate chain of the supplied input and checks them the way
+// ImportCertificate would, so that mistakes are reported with a precise
+// message instead of an opaque API error. Certificates in the chain are put
+// in order from the issuer of the certificate up to the root.
+func inspectImportCertificateInput(
+	input *svcsdk.ImportCertificateInput,
+	now time.Time,
+) error {
+	certs, err := parseCertificates(input.Certificate)
+	if err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+	switch len(certs) {
+	case 0:
+		return errors.New("certificate: no PEM encoded certificate found")
+	case 1:
+	default:
+		return fmt.Errorf(
+			"certificate: found %d certificates, intermediate certificates "+
+				"must be put in the certificate chain", len(certs),
+		)
+	}
+	leaf := certs[0]
+	if err := checkValidity(leaf, now); err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+	if err := checkImportPublicKey(leaf); err != nil {
+		return fmt.Errorf("certificate: %w", err)
+	}
+
+	key, err := parsePrivateKey(input.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("private key: %w", err)
+	}
+	if !publicKeyMatches(key, leaf.PublicKey) {
+		return errors.New("private key does not match the public key of the certificate")
+	}
+
+	if len(input.CertificateChain) == 0 {
+		return nil
+	}
+	chain, err := parseCertificates(input.CertificateChain)
+	if err != nil {
+		return fmt.Errorf("certificate chain: %w", err)
+	}
+	ordered, err := orderCertificateChain(leaf, chain)
+	if err != nil {
+		return fmt.Errorf("certificate chain: %w", err)
+	}
+	for _, cert := range ordered {
+		if err := checkValidity(cert, now); err != nil {
+			return fmt.Errorf("certificate chain: %q: %w", cert.Subject, err)
+		}
+	}
+	input.CertificateChain = encodeCertificates(ordered)
+	return nil
+}
+
+// parseCertificates returns the certificates in the supplied PEM data.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypeCertificate {
+			return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		return nil, errors.New("found data that is not PEM encoded")
+	}
+	return certs, nil
+}
+
+// joinPEM appends the PEM data of b to that of a, separating them with a
+// newline if a does not end with one, so that the last line of a and the
+// BEGIN line of b are not merged.
+func joinPEM(a string, b string) string {
+	if a != "" && b != "" && !strings.HasSuffix(a, "\n") {
+		a += "\n"
+	}
+	return a + b
+}
+
+// encodeCertificates returns the supplied certificates as PEM data.
+func encodeCertificates(certs []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certs {
+		_ = pem.Encode(&buf, &pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+// parsePrivateKey returns the private key in the supplied PEM data. ACM
+// only imports unencrypted keys.
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return nil, errors.New("found data after the private key")
+	}
+	switch block.Type {
+	case pemTypePrivateKey:
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case pemTypeRSAPrivateKey:
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case pemTypeECPrivateKey:
+		return x509.ParseECPrivateKey(block.Bytes)
+	case pemTypeEncryptedPrivateKey:
+		return nil, errors.New("private key must not be encrypted")
+	default:
+		return nil, fmt.Errorf("unexpected PEM block of type %q", block.Type)
+	}
+}
+
+// publicKeyMatches returns true if the public part of the supplied private
+// key is pub.
+func publicKeyMatches(key crypto.PrivateKey, pub crypto.PublicKey) bool {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return false
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(pub)
+}
+
+// checkImportPublicKey checks that the key type of the supplied certificate
+// is one ACM can import.
+func checkImportPublicKey(cert *x509.Certificate) error {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if size := pub.N.BitLen(); !acmRSAKeySizes[size] {
+			return fmt.Errorf("unsupported RSA key size %d", size)
+		}
+	case *ecdsa.PublicKey:
+		if !acmECCurves[pub.Curve] {
+			return fmt.Errorf("unsupported elliptic curve %s", pub.Curve.Params().Name)
+		}
+	default:
+		return fmt.Errorf("unsupported key algorithm %s", cert.PublicKeyAlgorithm)
+	}
+	return nil
+}
+
+// checkValidity checks that the supplied certificate is valid at now.
+func checkValidity(cert *x509.Certificate, now time.Time) error {
+	if now.Add(notBeforeClockSkew).Before(cert.NotBefore) {
+		return fmt.Errorf("not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// orderCertificateChain returns the supplied chain ordered from the issuer
+// of leaf up to the root, checking each signature along the way. All
+// certificates in chain must be part of the resulting path; the root may
+// be omitted.
+func orderCertificateChain(
+	leaf *x509.Certificate,
+	chain []*x509.Certificate,
+) ([]*x509.Certificate, error) {
+	remaining := append([]*x509.Certificate{}, chain...)
+	ordered := make([]*x509.Certificate, 0, len(chain))
+	child := leaf
+	for len(remaining) > 0 {
+		found := -1
+		for i, cert := range remaining {
+			if bytes.Equal(child.RawIssuer, cert.RawSubject) && child.CheckSignatureFrom(cert) == nil {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return nil, fmt.Errorf(
+				"no certificate found that issued %q, or its signature is invalid",
+				child.Subject,
+			)
+		}
+		child = remaining[found]
+		ordered = append(ordered, child)
+		remaining = append(remaining[:found], remaining[found+1:]...)
+		if bytes.Equal(child.RawIssuer, child.RawSubject) && len(remaining) > 0 {
+			return nil, fmt.Errorf(
+				"found %d certificate(s) that are not part of the path to the root %q",
+				len(remaining), child.Subject,
+			)
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,267 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+)
+
+// testIssuer is a certificate and its key, which issues test certificates.
+type testIssuer struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// issueTestCertificate returns a certificate for the supplied common name
+// and key, valid from notBefore for a day, issued by parent or self-signed
+// if parent is nil.
+func issueTestCertificate(
+	t *testing.T,
+	name string,
+	key crypto.Signer,
+	parent *testIssuer,
+	notBefore time.Time,
+) *testIssuer {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	issuer, issuerKey := template, key
+	if parent != nil {
+		issuer, issuerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), issuerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testIssuer{cert: cert, key: key}
+}
+
+func newECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func subjects(certs []*x509.Certificate) []string {
+	names := []string{}
+	for _, cert := range certs {
+		names = append(names, cert.Subject.CommonName)
+	}
+	return names
+}
+
+func TestOrderCertificateChain(t *testing.T) {
+	now := time.Now().Add(-time.Hour)
+	root := issueTestCertificate(t, "root", newECKey(t, elliptic.P256()), nil, now)
+	intermediate := issueTestCertificate(t, "intermediate", newECKey(t, elliptic.P256()), root, now)
+	leaf := issueTestCertificate(t, "leaf", newECKey(t, elliptic.P256()), intermediate, now)
+	// impostor has the subject of intermediate but another key, so the
+	// signature of leaf does not verify against it.
+	impostor := issueTestCertificate(t, "intermediate", newECKey(t, elliptic.P256()), root, now)
+	other := issueTestCertificate(t, "other", newECKey(t, elliptic.P256()), nil, now)
+
+	tests := []struct {
+		name    string
+		chain   []*testIssuer
+		want    []string
+		wantErr string
+	}{
+		{name: "ordered", chain: []*testIssuer{intermediate, root}, want: []string{"intermediate", "root"}},
+		{name: "reversed", chain: []*testIssuer{root, intermediate}, want: []string{"intermediate", "root"}},
+		{name: "root omitted", chain: []*testIssuer{intermediate}, want: []string{"intermediate"}},
+		{name: "intermediate missing", chain: []*testIssuer{root}, wantErr: `issued "CN=leaf"`},
+		{name: "invalid signature", chain: []*testIssuer{impostor, root}, wantErr: `issued "CN=leaf"`},
+		{name: "unrelated certificate", chain: []*testIssuer{intermediate, root, other}, wantErr: "not part of the path"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := []*x509.Certificate{}
+			for _, c := range tt.chain {
+				chain = append(chain, c.cert)
+			}
+			got, err := orderCertificateChain(leaf.cert, chain)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("orderCertificateChain error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("orderCertificateChain: %v", err)
+			}
+			if names := subjects(got); !slices.Equal(names, tt.want) {
+				t.Errorf("orderCertificateChain() = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestInspectImportCertificateInput(t *testing.T) {
+	now := time.Now()
+	encodeCert := func(c *testIssuer) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: c.cert.Raw})
+	}
+	encodeKey := func(t *testing.T, key crypto.Signer) []byte {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der})
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	smallRSAKey, err := rsa.GenerateKey(rand.Reader, 1536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey := newECKey(t, elliptic.P256())
+	p224Key := newECKey(t, elliptic.P224())
+	root := issueTestCertificate(t, "root", newECKey(t, elliptic.P384()), nil, now.Add(-time.Hour))
+	leaf := issueTestCertificate(t, "leaf", ecKey, root, now.Add(-time.Hour))
+	rsaLeaf := issueTestCertificate(t, "rsa", rsaKey, root, now.Add(-time.Hour))
+	expiredRoot := issueTestCertificate(t, "expired root", newECKey(t, elliptic.P384()), nil, now.Add(-48*time.Hour))
+	expiredRootLeaf := issueTestCertificate(t, "leaf", ecKey, expiredRoot, now.Add(-time.Hour))
+
+	tests := []struct {
+		name    string
+		input   svcsdk.ImportCertificateInput
+		wantErr string
+	}{
+		{
+			name:  "EC key",
+			input: svcsdk.ImportCertificateInput{Certificate: encodeCert(leaf), PrivateKey: encodeKey(t, ecKey)},
+		},
+		{
+			name: "PKCS #1 RSA key",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(rsaLeaf),
+				PrivateKey:  pem.EncodeToMemory(&pem.Block{Type: pemTypeRSAPrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}),
+			},
+		},
+		{
+			name: "with chain",
+			input: svcsdk.ImportCertificateInput{
+				Certificate:      encodeCert(leaf),
+				PrivateKey:       encodeKey(t, ecKey),
+				CertificateChain: encodeCert(root),
+			},
+		},
+		{
+			name:    "key of another certificate",
+			input:   svcsdk.ImportCertificateInput{Certificate: encodeCert(leaf), PrivateKey: encodeKey(t, rsaKey)},
+			wantErr: "private key does not match",
+		},
+		{
+			name: "encrypted key",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(leaf),
+				PrivateKey:  pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: []byte("secret")}),
+			},
+			wantErr: "must not be encrypted",
+		},
+		{
+			name: "data after the key",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(leaf),
+				PrivateKey:  append(encodeKey(t, ecKey), encodeCert(root)...),
+			},
+			wantErr: "found data after the private key",
+		},
+		{
+			name: "unsupported RSA key size",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(issueTestCertificate(t, "small", smallRSAKey, root, now.Add(-time.Hour))),
+				PrivateKey:  encodeKey(t, smallRSAKey),
+			},
+			wantErr: "unsupported RSA key size 1536",
+		},
+		{
+			name: "unsupported curve",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(issueTestCertificate(t, "p224", p224Key, root, now.Add(-time.Hour))),
+				PrivateKey:  encodeKey(t, p224Key),
+			},
+			wantErr: "unsupported elliptic curve P-224",
+		},
+		{
+			name: "intermediate in certificate",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: append(encodeCert(leaf), encodeCert(root)...),
+				PrivateKey:  encodeKey(t, ecKey),
+			},
+			wantErr: "found 2 certificates",
+		},
+		{
+			name: "not valid yet",
+			input: svcsdk.ImportCertificateInput{
+				Certificate: encodeCert(issueTestCertificate(t, "future", ecKey, root, now.Add(time.Hour))),
+				PrivateKey:  encodeKey(t, ecKey),
+			},
+			wantErr: "not valid before",
+		},
+		{
+			name: "expired chain",
+			input: svcsdk.ImportCertificateInput{
+				Certificate:      encodeCert(expiredRootLeaf),
+				PrivateKey:       encodeKey(t, ecKey),
+				CertificateChain: encodeCert(expiredRoot),
+			},
+			wantErr: "certificate chain: \"CN=expired root\": expired",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := inspectImportCertificateInput(&tt.input, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("inspectImportCertificateInput error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("inspectImportCertificateInput: %v", err)
+			}
+		})
+	}
+}
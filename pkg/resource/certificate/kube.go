@@ -0,0 +1,219 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlrtcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// errKubeClientsUnbound is returned when Kubernetes objects are accessed by
+// a resource manager whose factory was not bound to a controller manager.
+var errKubeClientsUnbound = errors.New("resource manager factory is not bound to a controller manager")
+
+// kubeClients are the Kubernetes clients of a controller manager that the
+// resource managers use beyond the Reconciler the ACK runtime passes them,
+// which only reads Opaque Secrets.
+type kubeClients struct {
+	// client reads from cache and writes to the API server. It is used for
+	// the Kubernetes objects the controller reads on every reconciliation,
+	// including its own CertificatePolicies and CertificateClasses.
+	client client.Client
+	// cache reads from the cache behind client. Unlike client, it also
+	// reads the metadata of Secrets, which the cache holds without their
+	// data.
+	cache client.Reader
+	// apiReader reads from the API server directly. Secrets are read with it
+	// so that the controller does not cache every Secret of the cluster.
+	apiReader client.Reader
+	// recorder records Events about Certificates.
+	recorder record.EventRecorder
+	// gatewaysIndexed is true if the Gateway API was installed when the
+	// controller started, in which case client lists Gateways by the
+	// Certificates they refer to with the gatewayCertificateRefIndex.
+	gatewaysIndexed bool
+}
+
+// boundKubeClients are the kubeClients of the controller manager the
+// resource manager factory is bound to. The resource managers and their
+// factory are generated without a field for them, so they are shared by
+// all the resource managers of the process, which only ever has one
+// controller manager.
+var boundKubeClients atomic.Pointer[kubeClients]
+
+// BindControllerManager sets up the resource managers produced by the
+// factory to access Kubernetes objects with the clients of the supplied
+// controller manager, in the namespaces the supplied configuration watches.
+// It must be called before the manager is started.
+func (f *resourceManagerFactory) BindControllerManager(mgr ctrlrt.Manager, cfg ackcfg.Config) error {
+	namespaces, err := cfg.GetWatchNamespaces()
+	if err != nil {
+		return err
+	}
+	kube, err := newKubeClients(mgr, namespaces)
+	if err != nil {
+		return err
+	}
+	boundKubeClients.Store(&kube)
+	return nil
+}
+
+// newKubeClients returns the kubeClients of the supplied controller manager.
+// The cache of the manager is limited to the label selectors of the watched
+// Certificates, which would hide Ingresses, Services and Gateways, so the
+// client reads from a cache of its own that the manager starts. The cache
+// holds the namespaced objects of the supplied namespaces, or of all
+// namespaces if none are supplied, and the cluster scoped objects, such as
+// CertificatePolicies, CertificateClasses and Namespaces, of the whole
+// cluster. The Secrets imported by Certificates and the
+// CertificateSecretGrants are watched with it. With a watch namespace, the controller therefore needs a
+// ClusterRole to list and watch the cluster scoped objects, which the Helm
+// chart creates.
+func newKubeClients(mgr ctrlrt.Manager, namespaces []string) (kubeClients, error) {
+	var defaultNamespaces map[string]ctrlrtcache.Config
+	if len(namespaces) > 0 {
+		defaultNamespaces = map[string]ctrlrtcache.Config{}
+		for _, namespace := range namespaces {
+			defaultNamespaces[namespace] = ctrlrtcache.Config{}
+		}
+	}
+	cache, err := ctrlrtcache.New(mgr.GetConfig(), ctrlrtcache.Options{
+		HTTPClient:        mgr.GetHTTPClient(),
+		Scheme:            mgr.GetScheme(),
+		Mapper:            mgr.GetRESTMapper(),
+		DefaultNamespaces: defaultNamespaces,
+	})
+	if err != nil {
+		return kubeClients{}, err
+	}
+	gatewaysIndexed, err := indexGatewayCertificateRefs(context.Background(), cache)
+	if err != nil {
+		return kubeClients{}, err
+	}
+	if err = indexImportSources(context.Background(), cache); err != nil {
+		return kubeClients{}, err
+	}
+	if err = mgr.Add(cache); err != nil {
+		return kubeClients{}, err
+	}
+	if err = watchImportSources(mgr, cache, mgr.GetAPIReader()); err != nil {
+		return kubeClients{}, err
+	}
+	if err = watchSecretGrants(mgr, cache, mgr.GetAPIReader()); err != nil {
+		return kubeClients{}, err
+	}
+	kc, err := client.New(mgr.GetConfig(), client.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+		Cache: &client.CacheOptions{
+			Reader:       cache,
+			DisableFor:   []client.Object{&corev1.Secret{}},
+			Unstructured: true,
+		},
+	})
+	if err != nil {
+		return kubeClients{}, err
+	}
+	return kubeClients{
+		client:          kc,
+		cache:           cache,
+		apiReader:       mgr.GetAPIReader(),
+		recorder:        mgr.GetEventRecorderFor(eventComponent), //nolint:staticcheck
+		gatewaysIndexed: gatewaysIndexed,
+	}, nil
+}
+
+// kube returns the Kubernetes clients of the resource manager, which are
+// all nil until the factory is bound to a controller manager.
+func (rm *resourceManager) kube() kubeClients {
+	if kube := boundKubeClients.Load(); kube != nil {
+		return *kube
+	}
+	return kubeClients{}
+}
+
+// kubeClient returns the client the resource manager reads and writes
+// Kubernetes objects with.
+func (rm *resourceManager) kubeClient() (client.Client, error) {
+	kc := rm.kube().client
+	if kc == nil {
+		return nil, errKubeClientsUnbound
+	}
+	return kc, nil
+}
+
+// boundReconciler is the reconciler of the Certificates. The ACK runtime
+// creates it when the service controller is bound to the controller
+// manager and only hands it to the resource managers, so the first
+// resource manager that reads a Certificate records it here for the
+// controllers that watch the objects Certificates depend on.
+var boundReconciler atomic.Pointer[acktypes.Reconciler]
+
+// bindReconciler records the reconciler of the resource manager as the
+// boundReconciler.
+func (rm *resourceManager) bindReconciler() {
+	if rm.rr != nil && boundReconciler.Load() == nil {
+		rr := rm.rr
+		boundReconciler.CompareAndSwap(nil, &rr)
+	}
+}
+
+// certificateReconciler syncs a Certificate with the boundReconciler on
+// behalf of the controllers that watch the objects Certificates depend on.
+type certificateReconciler struct {
+	// reader reads the Certificate once it is synced.
+	reader client.Reader
+}
+
+// Reconcile syncs the Certificate with the supplied name. The Certificate
+// controller keeps requeuing a synced Certificate on its own schedule, so a
+// requeue the reconciler asks for is only kept until the Certificate is
+// synced, for example while a re-import awaits approval.
+func (r *certificateReconciler) Reconcile(
+	ctx context.Context,
+	req reconcile.Request,
+) (reconcile.Result, error) {
+	rr := boundReconciler.Load()
+	if rr == nil {
+		// No Certificate was read yet, so their initial sync reads the
+		// changed object.
+		return reconcile.Result{}, nil
+	}
+	res, err := (*rr).Reconcile(ctx, req)
+	if err != nil {
+		return res, err
+	}
+	ko := &svcapitypes.Certificate{}
+	if err := r.reader.Get(ctx, req.NamespacedName, ko); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	if synced := ackcondition.Synced(&resource{ko}); synced != nil && synced.Status == corev1.ConditionTrue {
+		return reconcile.Result{}, nil
+	}
+	return res, nil
+}
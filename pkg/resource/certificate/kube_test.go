@@ -0,0 +1,82 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// stubReconciler is an acktypes.Reconciler that counts its reconciliations
+// and returns a fixed result.
+type stubReconciler struct {
+	acktypes.Reconciler
+	result     reconcile.Result
+	reconciled int
+}
+
+func (r *stubReconciler) Reconcile(context.Context, reconcile.Request) (reconcile.Result, error) {
+	r.reconciled++
+	return r.result, nil
+}
+
+func TestCertificateReconciler(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []*ackv1alpha1.Condition
+		want       reconcile.Result
+	}{
+		{
+			name:       "synced",
+			conditions: []*ackv1alpha1.Condition{{Type: ackv1alpha1.ConditionTypeResourceSynced, Status: corev1.ConditionTrue}},
+			want:       reconcile.Result{},
+		},
+		{
+			name:       "not synced",
+			conditions: []*ackv1alpha1.Condition{{Type: ackv1alpha1.ConditionTypeResourceSynced, Status: corev1.ConditionFalse}},
+			want:       reconcile.Result{RequeueAfter: requeuePending},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := newTestCertificate(svcapitypes.CertificateSpec{}).ko
+			ko.Status.Conditions = tt.conditions
+			stub := &stubReconciler{result: reconcile.Result{RequeueAfter: requeuePending}}
+			var rr acktypes.Reconciler = stub
+			previous := boundReconciler.Swap(&rr)
+			t.Cleanup(func() { boundReconciler.Store(previous) })
+
+			r := &certificateReconciler{reader: newFakeKubeClient(t, ko)}
+			req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name}}
+			got, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile: %v", err)
+			}
+			if stub.reconciled != 1 {
+				t.Errorf("certificate reconciled %d times, want once", stub.reconciled)
+			}
+			if got != tt.want {
+				t.Errorf("Reconcile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -235,6 +235,15 @@ func (rm *resourceManager) LateInitialize(
 		if observedKo.Spec.SubjectAlternativeNames != nil && latestKo.Spec.SubjectAlternativeNames == nil {
 			latestKo.Spec.SubjectAlternativeNames = observedKo.Spec.SubjectAlternativeNames
 		}
+		// Options are late initialized as a whole, so the export preference
+		// of Options that are set without one is filled in separately.
+		if observedKo.Spec.Options != nil && observedKo.Spec.Options.Export != nil &&
+			latestKo.Spec.Options != nil && latestKo.Spec.Options.Export == nil {
+			latestKo.Spec.Options.Export = observedKo.Spec.Options.Export
+		}
+	}
+	if requeueAfter := certificateRequeueAfter(rm.concreteResource(observed).ko, time.Now()); requeueAfter > 0 {
+		return rm.lateInitializeAndRequeue(observed, latestCopy, requeueAfter)
 	}
 
 	lateInitializedRes := rm.lateInitializeFromReadOneOutput(observed, latestCopy)
@@ -314,13 +323,7 @@ func (rm *resourceManager) EnsureTags(
 		// Should never happen... if it does, it's buggy code.
 		panic("resource manager's EnsureTags method received resource with nil CR object")
 	}
-	defaultTags := ackrt.GetDefaultTags(&rm.cfg, r.ko, md)
-	var existingTags []*svcapitypes.Tag
-	existingTags = r.ko.Spec.Tags
-	resourceTags, keyOrder := convertToOrderedACKTags(existingTags)
-	tags := acktags.Merge(resourceTags, defaultTags)
-	r.ko.Spec.Tags = fromACKTags(tags, keyOrder)
-	return nil
+	return rm.ensureTags(ctx, r, md)
 }
 
 // FilterSystemTags removes system-managed tags from the resource's tag collection
@@ -347,11 +350,7 @@ func (rm *resourceManager) FilterSystemTags(res acktypes.AWSResource, systemTags
 	if r == nil || r.ko == nil {
 		return
 	}
-	var existingTags []*svcapitypes.Tag
-	existingTags = r.ko.Spec.Tags
-	resourceTags, tagKeyOrder := convertToOrderedACKTags(existingTags)
-	ignoreSystemTags(resourceTags, systemTags)
-	r.ko.Spec.Tags = fromACKTags(resourceTags, tagKeyOrder)
+	rm.filterSystemTags(r, systemTags)
 }
 
 // mirrorAWSTags ensures that AWS tags are included in the desired resource
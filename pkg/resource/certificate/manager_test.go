@@ -0,0 +1,902 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	ctrlreconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/acm-controller/pkg/fakeacm"
+)
+
+const (
+	testNamespace = "default"
+	testCAARN     = "arn:aws:acm-pca:us-west-2:111122223333:certificate-authority/12345678-1234-1234-1234-123456789012"
+)
+
+// fakeReconciler is an acktypes.Reconciler that reads and writes Secrets
+// held in memory, by namespace/name and key.
+type fakeReconciler struct {
+	secrets map[string]map[string]string
+}
+
+func newFakeReconciler() *fakeReconciler {
+	return &fakeReconciler{secrets: map[string]map[string]string{}}
+}
+
+func (r *fakeReconciler) Reconcile(context.Context, ctrlreconcile.Request) (ctrlreconcile.Result, error) {
+	return ctrlreconcile.Result{}, nil
+}
+
+func (r *fakeReconciler) SecretValueFromReference(
+	ctx context.Context,
+	ref *ackv1alpha1.SecretKeyReference,
+) (string, error) {
+	if ref == nil {
+		return "", nil
+	}
+	value, ok := r.secrets[ref.Namespace+"/"+ref.Name][ref.Key]
+	if !ok {
+		return "", ackerr.SecretNotFound
+	}
+	return value, nil
+}
+
+func (r *fakeReconciler) WriteToSecret(
+	ctx context.Context,
+	value string,
+	namespace string,
+	name string,
+	key string,
+) error {
+	nn := namespace + "/" + name
+	if r.secrets[nn] == nil {
+		r.secrets[nn] = map[string]string{}
+	}
+	r.secrets[nn][key] = value
+	return nil
+}
+
+// setSecret sets the value of a key of a Secret in the test namespace.
+func (r *fakeReconciler) setSecret(name string, key string, value string) {
+	_ = r.WriteToSecret(context.Background(), value, testNamespace, name, key)
+}
+
+func secretKeyRef(name string, key string) *ackv1alpha1.SecretKeyReference {
+	return &ackv1alpha1.SecretKeyReference{
+		SecretReference: corev1.SecretReference{Name: name, Namespace: testNamespace},
+		Key:             key,
+	}
+}
+
+// newTestResourceManager returns a resource manager whose ACM client is
+// served by a new fakeacm.Server that keeps public certificates pending
+// validation, and whose Kubernetes clients read from an empty fake client.
+func newTestResourceManager(t *testing.T) (*resourceManager, *fakeacm.Server, *fakeReconciler) {
+	t.Helper()
+	srv, err := fakeacm.New(fakeacm.Options{ValidationDelay: -1})
+	if err != nil {
+		t.Fatalf("creating fake ACM: %v", err)
+	}
+	kc := newFakeKubeClient(t)
+	rr := newFakeReconciler()
+	rm, err := newResourceManager(
+		ackcfg.Config{}, srv.Config(), logr.Discard(), ackmetrics.NewMetrics("acm"), rr,
+		"111122223333", "us-west-2",
+	)
+	if err != nil {
+		t.Fatalf("creating resource manager: %v", err)
+	}
+	rm.sdkapi = srv.Client()
+	bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+	return rm, srv, rr
+}
+
+// bindKubeClients binds the resource managers to the supplied Kubernetes
+// clients until the test ends.
+func bindKubeClients(t *testing.T, kube kubeClients) {
+	t.Helper()
+	previous := boundKubeClients.Swap(&kube)
+	t.Cleanup(func() { boundKubeClients.Store(previous) })
+}
+
+// newFakeKubeClient returns a fake Kubernetes client holding the supplied
+// objects, which knows the Kubernetes and controller types.
+func newFakeKubeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newTestCertificate(spec svcapitypes.CertificateSpec) *resource {
+	return &resource{&svcapitypes.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: testNamespace, UID: "test-uid"},
+		Spec:       spec,
+	}}
+}
+
+func certificateARN(r *resource) string {
+	if r.ko.Status.ACKResourceMetadata == nil || r.ko.Status.ACKResourceMetadata.ARN == nil {
+		return ""
+	}
+	return string(*r.ko.Status.ACKResourceMetadata.ARN)
+}
+
+func describeCertificate(t *testing.T, srv *fakeacm.Server, arn string) *svcsdktypes.CertificateDetail {
+	t.Helper()
+	resp, err := srv.Client().DescribeCertificate(context.Background(), &svcsdk.DescribeCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		t.Fatalf("describing %s: %v", arn, err)
+	}
+	return resp.Certificate
+}
+
+func certificateTags(t *testing.T, srv *fakeacm.Server, arn string) map[string]string {
+	t.Helper()
+	resp, err := srv.Client().ListTagsForCertificate(context.Background(), &svcsdk.ListTagsForCertificateInput{
+		CertificateArn: aws.String(arn),
+	})
+	if err != nil {
+		t.Fatalf("listing tags of %s: %v", arn, err)
+	}
+	tags := map[string]string{}
+	for _, tag := range resp.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
+// createCertificate requests or imports the certificate of the supplied spec
+// with sdkCreate.
+func createCertificate(t *testing.T, rm *resourceManager, spec svcapitypes.CertificateSpec) *resource {
+	t.Helper()
+	created, err := rm.sdkCreate(context.Background(), newTestCertificate(spec))
+	if err != nil {
+		t.Fatalf("sdkCreate: %v", err)
+	}
+	return created
+}
+
+// selfSignedCertificate returns a PEM encoded self-signed certificate for
+// the supplied domain valid until notAfter, and its PEM encoded private key.
+func selfSignedCertificate(t *testing.T, domain string, notAfter time.Time) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    notAfter.Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func isTerminal(err error) bool {
+	var terminal *ackerr.TerminalError
+	return errors.As(err, &terminal)
+}
+
+func isRequeue(err error) bool {
+	var requeue *ackrequeue.RequeueNeededAfter
+	return errors.As(err, &requeue)
+}
+
+func TestSdkCreate(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         svcapitypes.CertificateSpec
+		wantTerminal bool
+		check        func(t *testing.T, detail *svcsdktypes.CertificateDetail, tags map[string]string)
+	}{
+		{
+			name: "public certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.org"),
+				SubjectAlternativeNames: aws.StringSlice([]string{"api.example.org"}),
+				Tags:                    []*svcapitypes.Tag{{Key: aws.String("team"), Value: aws.String("web")}},
+			},
+			check: func(t *testing.T, detail *svcsdktypes.CertificateDetail, tags map[string]string) {
+				if detail.Type != svcsdktypes.CertificateTypeAmazonIssued {
+					t.Errorf("Type = %s, want %s", detail.Type, svcsdktypes.CertificateTypeAmazonIssued)
+				}
+				if detail.Status != svcsdktypes.CertificateStatusPendingValidation {
+					t.Errorf("Status = %s, want %s", detail.Status, svcsdktypes.CertificateStatusPendingValidation)
+				}
+				if detail.KeyAlgorithm != svcsdktypes.KeyAlgorithm("RSA-2048") {
+					t.Errorf("KeyAlgorithm = %s, want RSA-2048", detail.KeyAlgorithm)
+				}
+				for _, dv := range detail.DomainValidationOptions {
+					if dv.ValidationMethod != svcsdktypes.ValidationMethodDns {
+						t.Errorf("ValidationMethod of %s = %s, want DNS", aws.ToString(dv.DomainName), dv.ValidationMethod)
+					}
+				}
+				if tags["team"] != "web" {
+					t.Errorf("tags = %v, want team=web", tags)
+				}
+			},
+		},
+		{
+			name: "exportable public certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				ExportTo:   secretKeyRef("exported", "tls.crt"),
+			},
+			check: func(t *testing.T, detail *svcsdktypes.CertificateDetail, tags map[string]string) {
+				if detail.Options == nil || detail.Options.Export != svcsdktypes.CertificateExportEnabled {
+					t.Errorf("Options = %+v, want Export ENABLED", detail.Options)
+				}
+			},
+		},
+		{
+			name: "EC key algorithm",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:   aws.String("www.example.org"),
+				KeyAlgorithm: aws.String("EC_prime256v1"),
+			},
+			check: func(t *testing.T, detail *svcsdktypes.CertificateDetail, tags map[string]string) {
+				if detail.KeyAlgorithm != svcsdktypes.KeyAlgorithm("EC-prime256v1") {
+					t.Errorf("KeyAlgorithm = %s, want EC-prime256v1", detail.KeyAlgorithm)
+				}
+			},
+		},
+		{
+			name: "private certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+			},
+			check: func(t *testing.T, detail *svcsdktypes.CertificateDetail, tags map[string]string) {
+				if detail.Type != svcsdktypes.CertificateTypePrivate {
+					t.Errorf("Type = %s, want %s", detail.Type, svcsdktypes.CertificateTypePrivate)
+				}
+				if detail.Status != svcsdktypes.CertificateStatusIssued {
+					t.Errorf("Status = %s, want %s", detail.Status, svcsdktypes.CertificateStatusIssued)
+				}
+			},
+		},
+		{
+			name: "request with a private key",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				PrivateKey: secretKeyRef("key", "tls.key"),
+			},
+			wantTerminal: true,
+		},
+		{
+			name: "invalid tag",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				Tags:       []*svcapitypes.Tag{{Key: aws.String("aws:reserved"), Value: aws.String("x")}},
+			},
+			wantTerminal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, _ := newTestResourceManager(t)
+			created, err := rm.sdkCreate(context.Background(), newTestCertificate(tt.spec))
+			if tt.wantTerminal {
+				if !isTerminal(err) {
+					t.Fatalf("sdkCreate error = %v, want a terminal error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkCreate: %v", err)
+			}
+			arn := certificateARN(created)
+			if arn == "" {
+				t.Fatal("sdkCreate did not set the ARN")
+			}
+			tt.check(t, describeCertificate(t, srv, arn), certificateTags(t, srv, arn))
+		})
+	}
+}
+
+func TestSdkFind(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource
+		// wantNotFound is true if sdkFind returns ackerr.NotFound.
+		wantNotFound bool
+		check        func(t *testing.T, ko *svcapitypes.Certificate)
+	}{
+		{
+			name: "not created",
+			setup: func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource {
+				return newTestCertificate(svcapitypes.CertificateSpec{DomainName: aws.String("www.example.org")})
+			},
+			wantNotFound: true,
+		},
+		{
+			name: "deleted",
+			setup: func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource {
+				r := createCertificate(t, rm, svcapitypes.CertificateSpec{DomainName: aws.String("www.example.org")})
+				if _, err := srv.Client().DeleteCertificate(context.Background(), &svcsdk.DeleteCertificateInput{
+					CertificateArn: aws.String(certificateARN(r)),
+				}); err != nil {
+					t.Fatal(err)
+				}
+				return r
+			},
+			wantNotFound: true,
+		},
+		{
+			name: "pending validation",
+			setup: func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource {
+				return createCertificate(t, rm, svcapitypes.CertificateSpec{
+					DomainName: aws.String("www.example.org"),
+					Tags:       []*svcapitypes.Tag{{Key: aws.String("team"), Value: aws.String("web")}},
+				})
+			},
+			check: func(t *testing.T, ko *svcapitypes.Certificate) {
+				if got := aws.ToString(ko.Status.Status); got != string(svcsdktypes.CertificateStatusPendingValidation) {
+					t.Errorf("Status.Status = %q, want %q", got, svcsdktypes.CertificateStatusPendingValidation)
+				}
+				if got := aws.ToString(ko.Spec.DomainName); got != "www.example.org" {
+					t.Errorf("Spec.DomainName = %q, want www.example.org", got)
+				}
+				if got := aws.ToString(ko.Spec.KeyAlgorithm); got != "RSA-2048" {
+					t.Errorf("Spec.KeyAlgorithm = %q, want RSA-2048", got)
+				}
+				if len(ko.Status.DomainValidations) == 0 || ko.Status.DomainValidations[0].ResourceRecord == nil {
+					t.Errorf("Status.DomainValidations = %v, want the DNS validation record", ko.Status.DomainValidations)
+				}
+				if len(ko.Spec.Tags) != 1 || aws.ToString(ko.Spec.Tags[0].Key) != "team" || aws.ToString(ko.Spec.Tags[0].Value) != "web" {
+					t.Errorf("Spec.Tags = %v, want team=web", ko.Spec.Tags)
+				}
+				if ko.Status.Serial != nil {
+					t.Errorf("Status.Serial = %q, want none before issuance", *ko.Status.Serial)
+				}
+			},
+		},
+		{
+			name: "issued",
+			setup: func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource {
+				r := createCertificate(t, rm, svcapitypes.CertificateSpec{DomainName: aws.String("www.example.org")})
+				if err := srv.Issue(certificateARN(r)); err != nil {
+					t.Fatal(err)
+				}
+				return r
+			},
+			check: func(t *testing.T, ko *svcapitypes.Certificate) {
+				if got := aws.ToString(ko.Status.Status); got != string(svcsdktypes.CertificateStatusIssued) {
+					t.Errorf("Status.Status = %q, want %q", got, svcsdktypes.CertificateStatusIssued)
+				}
+				if ko.Status.Serial == nil || ko.Status.IssuedAt == nil || ko.Status.NotAfter == nil {
+					t.Errorf("Status.Serial, IssuedAt and NotAfter = %v, %v, %v, want them set",
+						ko.Status.Serial, ko.Status.IssuedAt, ko.Status.NotAfter)
+				}
+			},
+		},
+		{
+			name: "failed validation",
+			setup: func(t *testing.T, rm *resourceManager, srv *fakeacm.Server) *resource {
+				r := createCertificate(t, rm, svcapitypes.CertificateSpec{DomainName: aws.String("www.example.org")})
+				if err := srv.Fail(certificateARN(r), svcsdktypes.FailureReasonAdditionalVerificationRequired); err != nil {
+					t.Fatal(err)
+				}
+				return r
+			},
+			check: func(t *testing.T, ko *svcapitypes.Certificate) {
+				if got := aws.ToString(ko.Status.Status); got != string(svcsdktypes.CertificateStatusFailed) {
+					t.Errorf("Status.Status = %q, want %q", got, svcsdktypes.CertificateStatusFailed)
+				}
+				if got := aws.ToString(ko.Status.FailureReason); got != string(svcsdktypes.FailureReasonAdditionalVerificationRequired) {
+					t.Errorf("Status.FailureReason = %q, want %q", got, svcsdktypes.FailureReasonAdditionalVerificationRequired)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, _ := newTestResourceManager(t)
+			r := tt.setup(t, rm, srv)
+			latest, err := rm.sdkFind(context.Background(), r)
+			if tt.wantNotFound {
+				if err != ackerr.NotFound {
+					t.Fatalf("sdkFind error = %v, want %v", err, ackerr.NotFound)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkFind: %v", err)
+			}
+			tt.check(t, latest.ko)
+		})
+	}
+}
+
+func TestSdkUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec svcapitypes.CertificateSpec
+		// update changes the desired spec and returns the delta.
+		update       func(ko *svcapitypes.Certificate, latest *svcapitypes.Certificate) *ackcompare.Delta
+		wantTerminal bool
+		check        func(t *testing.T, srv *fakeacm.Server, arn string)
+	}{
+		{
+			name: "tags",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				Tags: []*svcapitypes.Tag{
+					{Key: aws.String("team"), Value: aws.String("web")},
+					{Key: aws.String("stale"), Value: aws.String("yes")},
+				},
+			},
+			update: func(ko *svcapitypes.Certificate, latest *svcapitypes.Certificate) *ackcompare.Delta {
+				ko.Spec.Tags = []*svcapitypes.Tag{
+					{Key: aws.String("team"), Value: aws.String("platform")},
+					{Key: aws.String("env"), Value: aws.String("prod")},
+				}
+				delta := ackcompare.NewDelta()
+				delta.Add("Spec.Tags", ko.Spec.Tags, latest.Spec.Tags)
+				return delta
+			},
+			check: func(t *testing.T, srv *fakeacm.Server, arn string) {
+				tags := certificateTags(t, srv, arn)
+				want := map[string]string{"team": "platform", "env": "prod"}
+				if len(tags) != len(want) || tags["team"] != want["team"] || tags["env"] != want["env"] {
+					t.Errorf("tags = %v, want %v", tags, want)
+				}
+			},
+		},
+		{
+			name: "certificate transparency logging",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("www.example.org")},
+			update: func(ko *svcapitypes.Certificate, latest *svcapitypes.Certificate) *ackcompare.Delta {
+				ko.Spec.Options = &svcapitypes.CertificateOptions{
+					CertificateTransparencyLoggingPreference: aws.String(string(svcsdktypes.CertificateTransparencyLoggingPreferenceDisabled)),
+				}
+				delta := ackcompare.NewDelta()
+				delta.Add("Spec.Options", ko.Spec.Options, latest.Spec.Options)
+				return delta
+			},
+			check: func(t *testing.T, srv *fakeacm.Server, arn string) {
+				detail := describeCertificate(t, srv, arn)
+				if detail.Options == nil ||
+					detail.Options.CertificateTransparencyLoggingPreference != svcsdktypes.CertificateTransparencyLoggingPreferenceDisabled {
+					t.Errorf("Options = %+v, want CertificateTransparencyLoggingPreference DISABLED", detail.Options)
+				}
+			},
+		},
+		{
+			name: "options of an imported certificate",
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("imported", "tls.crt"),
+				PrivateKey:  secretKeyRef("imported", "tls.key"),
+			},
+			update: func(ko *svcapitypes.Certificate, latest *svcapitypes.Certificate) *ackcompare.Delta {
+				ko.Spec.Options = &svcapitypes.CertificateOptions{
+					CertificateTransparencyLoggingPreference: aws.String(string(svcsdktypes.CertificateTransparencyLoggingPreferenceEnabled)),
+				}
+				delta := ackcompare.NewDelta()
+				delta.Add("Spec.Options", ko.Spec.Options, latest.Spec.Options)
+				return delta
+			},
+			wantTerminal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, rr := newTestResourceManager(t)
+			certPEM, keyPEM := selfSignedCertificate(t, "imported.example.org", time.Now().Add(24*time.Hour))
+			rr.setSecret("imported", "tls.crt", certPEM)
+			rr.setSecret("imported", "tls.key", keyPEM)
+
+			created := createCertificate(t, rm, tt.spec)
+			latest, err := rm.sdkFind(context.Background(), created)
+			if err != nil {
+				t.Fatalf("sdkFind: %v", err)
+			}
+			desired := &resource{latest.ko.DeepCopy()}
+			delta := tt.update(desired.ko, latest.ko)
+
+			_, err = rm.sdkUpdate(context.Background(), desired, latest, delta)
+			if tt.wantTerminal {
+				if !isTerminal(err) {
+					t.Fatalf("sdkUpdate error = %v, want a terminal error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkUpdate: %v", err)
+			}
+			tt.check(t, srv, certificateARN(latest))
+		})
+	}
+}
+
+func TestSdkDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		// inUseBy are the resources the certificate is associated with.
+		inUseBy     []string
+		wantErrCode string
+	}{
+		{
+			name: "unused certificate",
+		},
+		{
+			name:        "certificate in use",
+			inUseBy:     []string{"arn:aws:elasticloadbalancing:us-west-2:111122223333:loadbalancer/app/web/0123456789abcdef"},
+			wantErrCode: "ResourceInUseException",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, _ := newTestResourceManager(t)
+			r := createCertificate(t, rm, svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+			})
+			arn := certificateARN(r)
+			if len(tt.inUseBy) > 0 {
+				if err := srv.SetInUseBy(arn, tt.inUseBy...); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			_, err := rm.sdkDelete(context.Background(), r)
+			if tt.wantErrCode != "" {
+				if got := awsErrorCode(err); got != tt.wantErrCode {
+					t.Fatalf("sdkDelete error = %v, want %s", err, tt.wantErrCode)
+				}
+				describeCertificate(t, srv, arn)
+				return
+			}
+			if err != nil {
+				t.Fatalf("sdkDelete: %v", err)
+			}
+			if _, err := rm.sdkFind(context.Background(), r); err != ackerr.NotFound {
+				t.Fatalf("sdkFind after sdkDelete error = %v, want %v", err, ackerr.NotFound)
+			}
+		})
+	}
+}
+
+func TestExportCertificate(t *testing.T) {
+	tests := []struct {
+		name string
+		spec svcapitypes.CertificateSpec
+		// issue issues a public certificate before it is exported.
+		issue       bool
+		wantErr     bool
+		wantKeyType string
+	}{
+		{
+			name: "no ExportTo",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+			},
+		},
+		{
+			name: "private certificate with the default key format",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+				ExportTo:                secretKeyRef("exported", "tls.crt"),
+			},
+			wantKeyType: "PRIVATE KEY",
+		},
+		{
+			name: "RSA key in the traditional format",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+				ExportTo:                secretKeyRef("exported", "tls.crt"),
+				ExportKeyFormat:         ptr(PrivateKeyFormatTraditional),
+			},
+			wantKeyType: "RSA PRIVATE KEY",
+		},
+		{
+			name: "EC key in the traditional format",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("internal.example.org"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+				KeyAlgorithm:            aws.String("EC_prime256v1"),
+				ExportTo:                secretKeyRef("exported", "tls.crt"),
+				ExportKeyFormat:         ptr(PrivateKeyFormatTraditional),
+			},
+			wantKeyType: "EC PRIVATE KEY",
+		},
+		{
+			name: "issued public certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				ExportTo:   secretKeyRef("exported", "tls.crt"),
+			},
+			issue:       true,
+			wantKeyType: "PRIVATE KEY",
+		},
+		{
+			name: "public certificate pending validation",
+			spec: svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				ExportTo:   secretKeyRef("exported", "tls.crt"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, rr := newTestResourceManager(t)
+			r := createCertificate(t, rm, tt.spec)
+			if tt.issue {
+				if err := srv.Issue(certificateARN(r)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := rm.exportCertificate(context.Background(), r)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("exportCertificate succeeded, want an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exportCertificate: %v", err)
+			}
+			secret := rr.secrets[testNamespace+"/exported"]
+			if tt.wantKeyType == "" {
+				if secret != nil {
+					t.Fatalf("exportCertificate wrote %v, want nothing", secret)
+				}
+				return
+			}
+			certs, err := parseCertificates([]byte(secret["tls.crt"]))
+			if err != nil || len(certs) == 0 {
+				t.Fatalf("tls.crt does not hold a certificate: %v", err)
+			}
+			block, _ := pem.Decode([]byte(secret["tls.key"]))
+			if block == nil || block.Type != tt.wantKeyType {
+				t.Fatalf("tls.key = %q, want a %s PEM block", secret["tls.key"], tt.wantKeyType)
+			}
+			key, err := parsePrivateKey([]byte(secret["tls.key"]))
+			if err != nil {
+				t.Fatalf("parsing tls.key: %v", err)
+			}
+			if !publicKeyMatches(key, certs[0].PublicKey) {
+				t.Fatal("tls.key does not match the certificate in tls.crt")
+			}
+		})
+	}
+}
+
+func TestImportCertificate(t *testing.T) {
+	now := time.Now()
+	certPEM, keyPEM := selfSignedCertificate(t, "imported.example.org", now.Add(24*time.Hour))
+	expiredPEM, expiredKeyPEM := selfSignedCertificate(t, "expired.example.org", now.Add(-time.Hour))
+	_, otherKeyPEM := selfSignedCertificate(t, "other.example.org", now.Add(24*time.Hour))
+
+	tests := []struct {
+		name string
+		// secrets are the keys of the Secret named "import".
+		secrets      map[string]string
+		spec         svcapitypes.CertificateSpec
+		wantTerminal bool
+		wantErr      bool
+	}{
+		{
+			name:    "certificate and private key",
+			secrets: map[string]string{"tls.crt": certPEM, "tls.key": keyPEM},
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+				Tags:        []*svcapitypes.Tag{{Key: aws.String("team"), Value: aws.String("web")}},
+			},
+		},
+		{
+			name:    "missing private key",
+			secrets: map[string]string{"tls.crt": certPEM},
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+			},
+			wantTerminal: true,
+		},
+		{
+			name:    "expired certificate",
+			secrets: map[string]string{"tls.crt": expiredPEM, "tls.key": expiredKeyPEM},
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+			},
+			wantTerminal: true,
+		},
+		{
+			name:    "private key of another certificate",
+			secrets: map[string]string{"tls.crt": certPEM, "tls.key": otherKeyPEM},
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+			},
+			wantTerminal: true,
+		},
+		{
+			name:    "request fields",
+			secrets: map[string]string{"tls.crt": certPEM, "tls.key": keyPEM},
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+				DomainName:  aws.String("imported.example.org"),
+			},
+			wantTerminal: true,
+		},
+		{
+			name: "missing Secret",
+			spec: svcapitypes.CertificateSpec{
+				Certificate: secretKeyRef("import", "tls.crt"),
+				PrivateKey:  secretKeyRef("import", "tls.key"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, srv, rr := newTestResourceManager(t)
+			for key, value := range tt.secrets {
+				rr.setSecret("import", key, value)
+			}
+
+			created, err := rm.sdkCreate(context.Background(), newTestCertificate(tt.spec))
+			switch {
+			case tt.wantTerminal:
+				if !isTerminal(err) {
+					t.Fatalf("sdkCreate error = %v, want a terminal error", err)
+				}
+				return
+			case tt.wantErr:
+				if err == nil || isTerminal(err) {
+					t.Fatalf("sdkCreate error = %v, want a retryable error", err)
+				}
+				return
+			case err != nil:
+				t.Fatalf("sdkCreate: %v", err)
+			}
+			arn := certificateARN(created)
+			detail := describeCertificate(t, srv, arn)
+			if detail.Type != svcsdktypes.CertificateTypeImported {
+				t.Errorf("Type = %s, want %s", detail.Type, svcsdktypes.CertificateTypeImported)
+			}
+			if got := aws.ToString(detail.DomainName); got != "imported.example.org" {
+				t.Errorf("DomainName = %q, want imported.example.org", got)
+			}
+			if tags := certificateTags(t, srv, arn); tags["team"] != "web" {
+				t.Errorf("tags = %v, want team=web", tags)
+			}
+		})
+	}
+}
+
+func TestLateInitializeOptionsExport(t *testing.T) {
+	enabled := string(svcsdktypes.CertificateExportEnabled)
+	disabled := string(svcsdktypes.CertificateExportDisabled)
+	loggingDisabled := string(svcsdktypes.CertificateTransparencyLoggingPreferenceDisabled)
+	tests := []struct {
+		name string
+		// created are the Options the certificate is requested with.
+		created *svcapitypes.CertificateOptions
+		// latest are the Options of the resource being late initialized.
+		latest *svcapitypes.CertificateOptions
+		want   *string
+	}{
+		{
+			name: "options unset",
+			want: &disabled,
+		},
+		{
+			name:    "options unset on an exportable certificate",
+			created: &svcapitypes.CertificateOptions{Export: &enabled},
+			want:    &enabled,
+		},
+		{
+			name:    "options set without export",
+			created: &svcapitypes.CertificateOptions{Export: &enabled, CertificateTransparencyLoggingPreference: &loggingDisabled},
+			latest:  &svcapitypes.CertificateOptions{CertificateTransparencyLoggingPreference: &loggingDisabled},
+			want:    &enabled,
+		},
+		{
+			name:    "export set",
+			created: &svcapitypes.CertificateOptions{Export: &enabled},
+			latest:  &svcapitypes.CertificateOptions{Export: &disabled},
+			want:    &disabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rm, _, _ := newTestResourceManager(t)
+			latest := createCertificate(t, rm, svcapitypes.CertificateSpec{
+				DomainName: aws.String("www.example.org"),
+				Options:    tt.created,
+			})
+			latest.ko.Spec.Options = tt.latest.DeepCopy()
+
+			res, err := rm.LateInitialize(context.Background(), latest)
+			if err != nil && !isRequeue(err) {
+				t.Fatalf("LateInitialize: %v", err)
+			}
+			options := res.(*resource).ko.Spec.Options
+			if options == nil {
+				t.Fatal("Options were not late initialized")
+			}
+			if aws.ToString(options.Export) != aws.ToString(tt.want) {
+				t.Errorf("Options.Export = %q, want %q", aws.ToString(options.Export), aws.ToString(tt.want))
+			}
+			if tt.latest != nil {
+				got, want := aws.ToString(options.CertificateTransparencyLoggingPreference), aws.ToString(tt.latest.CertificateTransparencyLoggingPreference)
+				if got != want {
+					t.Errorf("Options.CertificateTransparencyLoggingPreference = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
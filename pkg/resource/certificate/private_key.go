@@ -0,0 +1,257 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"crypto/aes"
+	"crypto/des"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	pkcs8 "github.com/youmark/pkcs8"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// PrivateKeyFormat is the encoding DecryptPrivateKey returns decrypted
+// private keys in, as set in the ExportKeyFormat of a Certificate.
+type PrivateKeyFormat = svcapitypes.PrivateKeyFormat
+
+const (
+	// PrivateKeyFormatPKCS8 encodes private keys of any type as PKCS #8
+	// "PRIVATE KEY" blocks.
+	PrivateKeyFormatPKCS8 = svcapitypes.PrivateKeyFormatPKCS8
+	// PrivateKeyFormatTraditional encodes RSA private keys as PKCS #1
+	// "RSA PRIVATE KEY" blocks and EC private keys as SEC 1
+	// "EC PRIVATE KEY" blocks.
+	PrivateKeyFormatTraditional = svcapitypes.PrivateKeyFormatTraditional
+)
+
+const (
+	// maxPBKDF2Iterations bounds the PBKDF2 iteration count of the private
+	// keys DecryptPrivateKey decrypts, so that malformed key derivation
+	// parameters cannot stall a reconcile.
+	maxPBKDF2Iterations = 1 << 20
+	// maxScryptWork bounds the product of the scrypt cost, block size and
+	// parallelization parameters of the private keys DecryptPrivateKey
+	// decrypts.
+	maxScryptWork = 1 << 20
+)
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidScrypt = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11591, 4, 11}
+	// oidAES prefixes the object identifiers of the AES ciphers.
+	oidAES        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+var (
+	// ErrNoPEMData is returned when the data to decrypt does not contain a
+	// PEM block.
+	ErrNoPEMData = errors.New("no PEM data found")
+	// ErrNotEncryptedPrivateKey is returned when the PEM block to decrypt
+	// is not an encrypted PKCS #8 private key.
+	ErrNotEncryptedPrivateKey = errors.New("PEM block is not an encrypted private key")
+	// ErrEncryptionParameters is returned when the encryption parameters of
+	// the private key to decrypt are malformed, or too costly to derive a
+	// key with.
+	ErrEncryptionParameters = errors.New("invalid private key encryption parameters")
+	// ErrDecryptPrivateKey is returned when the private key cannot be
+	// decrypted, e.g. because the passphrase is wrong.
+	ErrDecryptPrivateKey = errors.New("failed to decrypt private key")
+)
+
+// UnsupportedKeyError is returned when a decrypted private key is not of a
+// type or size ACM issues.
+type UnsupportedKeyError struct {
+	// Key describes the type, and size or curve, of the private key.
+	Key string
+}
+
+func (e *UnsupportedKeyError) Error() string {
+	return fmt.Sprintf("unsupported private key %s", e.Key)
+}
+
+// DecryptPrivateKey decrypts the encrypted PKCS #8 private key in the
+// supplied PEM data, as returned by ExportCertificate, and returns it PEM
+// encoded in the supplied format. The key is encoded according to its
+// decoded type, whatever the key algorithm of the certificate spec is:
+// certificates that were adopted or late-initialized may not have one.
+func DecryptPrivateKey(
+	encryptedPEM []byte,
+	passphrase []byte,
+	format PrivateKeyFormat,
+) ([]byte, error) {
+	block, _ := pem.Decode(encryptedPEM)
+	if block == nil {
+		return nil, ErrNoPEMData
+	}
+	if block.Type != pemTypeEncryptedPrivateKey {
+		return nil, fmt.Errorf("%w: found %q", ErrNotEncryptedPrivateKey, block.Type)
+	}
+	if err := checkEncryptionParameters(block.Bytes); err != nil {
+		return nil, err
+	}
+	key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptPrivateKey, err)
+	}
+	if err := checkPrivateKey(key); err != nil {
+		return nil, err
+	}
+
+	var out *pem.Block
+	switch format {
+	case PrivateKeyFormatPKCS8, "":
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("encoding private key: %w", err)
+		}
+		out = &pem.Block{Type: pemTypePrivateKey, Bytes: der}
+	case PrivateKeyFormatTraditional:
+		switch key := key.(type) {
+		case *rsa.PrivateKey:
+			out = &pem.Block{Type: pemTypeRSAPrivateKey, Bytes: x509.MarshalPKCS1PrivateKey(key)}
+		case *ecdsa.PrivateKey:
+			der, err := x509.MarshalECPrivateKey(key)
+			if err != nil {
+				return nil, fmt.Errorf("encoding private key: %w", err)
+			}
+			out = &pem.Block{Type: pemTypeECPrivateKey, Bytes: der}
+		}
+	default:
+		return nil, fmt.Errorf("unknown private key format %q", format)
+	}
+	return pem.EncodeToMemory(out), nil
+}
+
+// checkPrivateKey checks that the supplied private key is an RSA or EC key
+// of a size or curve ACM issues certificates for.
+func checkPrivateKey(key any) error {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if size := key.N.BitLen(); !acmRSAKeySizes[size] {
+			return &UnsupportedKeyError{Key: fmt.Sprintf("RSA %d", size)}
+		}
+	case *ecdsa.PrivateKey:
+		if key.Curve == nil || !acmECCurves[key.Curve] {
+			name := "unknown"
+			if key.Curve != nil {
+				name = key.Curve.Params().Name
+			}
+			return &UnsupportedKeyError{Key: "EC " + name}
+		}
+	default:
+		return &UnsupportedKeyError{Key: fmt.Sprintf("%T", key)}
+	}
+	return nil
+}
+
+// checkEncryptionParameters checks the PBES2 parameters of the supplied
+// encrypted PKCS #8 private key before it is decrypted: the cost of deriving
+// the key is set by the encrypted data itself, and the pkcs8 package panics
+// on initialization vectors or encrypted data that do not fit the block size
+// of the cipher.
+func checkEncryptionParameters(der []byte) error {
+	var info struct {
+		EncryptionAlgorithm pkix.AlgorithmIdentifier
+		EncryptedData       []byte
+	}
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return fmt.Errorf("%w: %v", ErrEncryptionParameters, err)
+	}
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		// pkcs8 only supports PBES2, and fails before decrypting anything.
+		return nil
+	}
+	var pbes2 struct {
+		KeyDerivationFunc pkix.AlgorithmIdentifier
+		EncryptionScheme  pkix.AlgorithmIdentifier
+	}
+	if _, err := asn1.Unmarshal(info.EncryptionAlgorithm.Parameters.FullBytes, &pbes2); err != nil {
+		return fmt.Errorf("%w: %v", ErrEncryptionParameters, err)
+	}
+	if err := checkKeyDerivationFunc(pbes2.KeyDerivationFunc); err != nil {
+		return err
+	}
+	return checkEncryptionScheme(pbes2.EncryptionScheme, info.EncryptedData)
+}
+
+// checkKeyDerivationFunc checks that deriving a key with the supplied PBKDF2
+// or scrypt parameters is bounded.
+func checkKeyDerivationFunc(kdf pkix.AlgorithmIdentifier) error {
+	switch {
+	case kdf.Algorithm.Equal(oidPBKDF2):
+		var params struct {
+			Salt           []byte
+			IterationCount int
+		}
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return fmt.Errorf("%w: %v", ErrEncryptionParameters, err)
+		}
+		if params.IterationCount < 1 || params.IterationCount > maxPBKDF2Iterations {
+			return fmt.Errorf("%w: PBKDF2 iteration count %d", ErrEncryptionParameters, params.IterationCount)
+		}
+	case kdf.Algorithm.Equal(oidScrypt):
+		var params struct {
+			Salt                     []byte
+			CostParameter            int
+			BlockSize                int
+			ParallelizationParameter int
+		}
+		if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+			return fmt.Errorf("%w: %v", ErrEncryptionParameters, err)
+		}
+		n, r, p := params.CostParameter, params.BlockSize, params.ParallelizationParameter
+		if n < 1 || r < 1 || p < 1 || n > maxScryptWork/r/p {
+			return fmt.Errorf("%w: scrypt parameters N=%d r=%d p=%d", ErrEncryptionParameters, n, r, p)
+		}
+	}
+	return nil
+}
+
+// checkEncryptionScheme checks that the initialization vector of the
+// supplied encryption scheme, and the encrypted data, fit the block size of
+// its cipher.
+func checkEncryptionScheme(scheme pkix.AlgorithmIdentifier, encryptedData []byte) error {
+	var blockSize int
+	switch {
+	case len(scheme.Algorithm) == len(oidAES)+1 && scheme.Algorithm[:len(oidAES)].Equal(oidAES):
+		blockSize = aes.BlockSize
+	case scheme.Algorithm.Equal(oidDESEDE3CBC):
+		blockSize = des.BlockSize
+	default:
+		// pkcs8 fails on unknown ciphers before decrypting anything.
+		return nil
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(scheme.Parameters.FullBytes, &iv); err != nil {
+		return fmt.Errorf("%w: %v", ErrEncryptionParameters, err)
+	}
+	if len(iv) != blockSize {
+		return fmt.Errorf("%w: initialization vector of %d bytes", ErrEncryptionParameters, len(iv))
+	}
+	if len(encryptedData) == 0 || len(encryptedData)%blockSize != 0 {
+		return fmt.Errorf("%w: encrypted data of %d bytes", ErrEncryptionParameters, len(encryptedData))
+	}
+	return nil
+}
@@ -0,0 +1,493 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	pkcs8 "github.com/youmark/pkcs8"
+)
+
+const testPassphrase = "passphrase"
+
+var (
+	oidAES256CBC = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	// oidRC2CBC is a cipher pkcs8 does not support.
+	oidRC2CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 2}
+)
+
+// testPrivateKeys returns a private key of each type and size ACM issues
+// certificates for, by name. RSA keys are slow to generate, so they are
+// generated once.
+var testPrivateKeys = sync.OnceValue(func() map[string]crypto.Signer {
+	keys := map[string]crypto.Signer{}
+	for _, bits := range []int{1024, 2048, 3072, 4096} {
+		key, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			panic(err)
+		}
+		keys[fmt.Sprintf("RSA %d", bits)] = key
+	}
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384(), elliptic.P521()} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		keys["EC "+curve.Params().Name] = key
+	}
+	return keys
+})
+
+// encryptPrivateKey returns the supplied key encrypted with the supplied
+// passphrase and options, PEM encoded the way ExportCertificate returns it.
+func encryptPrivateKey(tb testing.TB, key any, passphrase string, opts *pkcs8.Opts) []byte {
+	tb.Helper()
+	der, err := pkcs8.MarshalPrivateKey(key, []byte(passphrase), opts)
+	if err != nil {
+		tb.Fatalf("encrypting private key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: der})
+}
+
+// algorithmIdentifier returns the identifier of the supplied algorithm with
+// the supplied DER encoded parameters.
+func algorithmIdentifier(tb testing.TB, oid asn1.ObjectIdentifier, params any) pkix.AlgorithmIdentifier {
+	tb.Helper()
+	der, err := asn1.Marshal(params)
+	if err != nil {
+		tb.Fatalf("encoding parameters of %s: %v", oid, err)
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oid, Parameters: asn1.RawValue{FullBytes: der}}
+}
+
+func pbkdf2Algorithm(tb testing.TB, iterations int) pkix.AlgorithmIdentifier {
+	return algorithmIdentifier(tb, oidPBKDF2, struct {
+		Salt           []byte
+		IterationCount int
+	}{make([]byte, 8), iterations})
+}
+
+func scryptAlgorithm(tb testing.TB, n int, r int, p int) pkix.AlgorithmIdentifier {
+	return algorithmIdentifier(tb, oidScrypt, struct {
+		Salt                     []byte
+		CostParameter            int
+		BlockSize                int
+		ParallelizationParameter int
+	}{make([]byte, 8), n, r, p})
+}
+
+// pbes2PrivateKey returns a PEM encoded PBES2 encrypted private key with the
+// supplied key derivation function, encryption scheme and encrypted data,
+// none of which need to be valid.
+func pbes2PrivateKey(
+	tb testing.TB,
+	kdf pkix.AlgorithmIdentifier,
+	scheme pkix.AlgorithmIdentifier,
+	encryptedData []byte,
+) []byte {
+	tb.Helper()
+	der, err := asn1.Marshal(struct {
+		EncryptionAlgorithm pkix.AlgorithmIdentifier
+		EncryptedData       []byte
+	}{
+		EncryptionAlgorithm: algorithmIdentifier(tb, oidPBES2, struct {
+			KeyDerivationFunc pkix.AlgorithmIdentifier
+			EncryptionScheme  pkix.AlgorithmIdentifier
+		}{kdf, scheme}),
+		EncryptedData: encryptedData,
+	})
+	if err != nil {
+		tb.Fatalf("encoding encrypted private key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: der})
+}
+
+// decryptErrorTest is an input DecryptPrivateKey rejects.
+type decryptErrorTest struct {
+	name       string
+	data       []byte
+	passphrase string
+	format     PrivateKeyFormat
+	// wantErr is the error DecryptPrivateKey wraps, or nil if it returns an
+	// *UnsupportedKeyError.
+	wantErr error
+}
+
+// decryptErrorTests returns the inputs DecryptPrivateKey rejects, which are
+// also the seeds of FuzzDecryptPrivateKey.
+func decryptErrorTests(tb testing.TB) []decryptErrorTest {
+	p256 := testPrivateKeys()["EC P-256"]
+	p224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	rsa1536, err := rsa.GenerateKey(rand.Reader, 1536)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	aesIV := algorithmIdentifier(tb, oidAES256CBC, make([]byte, 16))
+	validKey := encryptPrivateKey(tb, p256, testPassphrase, nil)
+	return []decryptErrorTest{
+		{name: "empty", data: nil, wantErr: ErrNoPEMData},
+		{name: "not PEM", data: []byte("not a private key"), wantErr: ErrNoPEMData},
+		{name: "truncated PEM", data: validKey[:len(validKey)/2], wantErr: ErrNoPEMData},
+		{
+			name:    "unencrypted private key",
+			data:    pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: []byte{0x30, 0x00}}),
+			wantErr: ErrNotEncryptedPrivateKey,
+		},
+		{
+			name:    "not DER",
+			data:    pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: []byte("garbage")}),
+			wantErr: ErrEncryptionParameters,
+		},
+		{name: "wrong passphrase", data: validKey, passphrase: "wrong", wantErr: ErrDecryptPrivateKey},
+		{name: "empty passphrase", data: validKey, passphrase: "", wantErr: ErrDecryptPrivateKey},
+		{
+			name:       "unknown key format",
+			data:       validKey,
+			passphrase: testPassphrase,
+			format:     "PKCS1",
+			wantErr:    errors.New("unknown private key format"),
+		},
+		{
+			name:    "huge PBKDF2 iteration count",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1<<31-1), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "zero PBKDF2 iteration count",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 0), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "negative PBKDF2 iteration count",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, -1), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "malformed PBKDF2 parameters",
+			data:    pbes2PrivateKey(tb, algorithmIdentifier(tb, oidPBKDF2, 42), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "huge scrypt cost",
+			data:    pbes2PrivateKey(tb, scryptAlgorithm(tb, 1<<30, 8, 1), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "huge scrypt parallelization",
+			data:    pbes2PrivateKey(tb, scryptAlgorithm(tb, 1<<14, 8, 1<<20), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "overflowing scrypt parameters",
+			data:    pbes2PrivateKey(tb, scryptAlgorithm(tb, 1<<62, 1<<62, 1<<62), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "zero scrypt block size",
+			data:    pbes2PrivateKey(tb, scryptAlgorithm(tb, 1<<10, 0, 1), aesIV, make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name: "short AES initialization vector",
+			data: pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1),
+				algorithmIdentifier(tb, oidAES256CBC, make([]byte, 8)), make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name: "long AES initialization vector",
+			data: pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1),
+				algorithmIdentifier(tb, oidAES256CBC, make([]byte, 32)), make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name: "malformed AES initialization vector",
+			data: pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1),
+				algorithmIdentifier(tb, oidAES256CBC, 42), make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name: "long 3DES initialization vector",
+			data: pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1),
+				algorithmIdentifier(tb, oidDESEDE3CBC, make([]byte, 16)), make([]byte, 32)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "encrypted data not a multiple of the block size",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1), aesIV, make([]byte, 33)),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name:    "empty encrypted data",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1), aesIV, nil),
+			wantErr: ErrEncryptionParameters,
+		},
+		{
+			name: "unsupported cipher",
+			data: pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1),
+				algorithmIdentifier(tb, oidRC2CBC, make([]byte, 8)), make([]byte, 32)),
+			wantErr: ErrDecryptPrivateKey,
+		},
+		{
+			name:    "garbage encrypted data",
+			data:    pbes2PrivateKey(tb, pbkdf2Algorithm(tb, 1), aesIV, bytes.Repeat([]byte{0xff}, 64)),
+			wantErr: ErrDecryptPrivateKey,
+		},
+		{name: "EC P-224 key", data: encryptPrivateKey(tb, p224, testPassphrase, nil), passphrase: testPassphrase},
+		{name: "RSA 1536 key", data: encryptPrivateKey(tb, rsa1536, testPassphrase, nil), passphrase: testPassphrase},
+		{name: "Ed25519 key", data: encryptPrivateKey(tb, ed, testPassphrase, nil), passphrase: testPassphrase},
+	}
+}
+
+func TestDecryptPrivateKeyErrors(t *testing.T) {
+	for _, tt := range decryptErrorTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			format := tt.format
+			if format == "" {
+				format = PrivateKeyFormatPKCS8
+			}
+			out, err := DecryptPrivateKey(tt.data, []byte(tt.passphrase), format)
+			if err == nil {
+				t.Fatalf("DecryptPrivateKey returned %q, want an error", out)
+			}
+			var unsupported *UnsupportedKeyError
+			switch {
+			case tt.wantErr == nil:
+				if !errors.As(err, &unsupported) {
+					t.Fatalf("DecryptPrivateKey error = %v, want an *UnsupportedKeyError", err)
+				}
+			case errors.Is(err, tt.wantErr):
+			case !strings.Contains(err.Error(), tt.wantErr.Error()):
+				t.Fatalf("DecryptPrivateKey error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecryptPrivateKeyRoundTrip(t *testing.T) {
+	options := map[string]*pkcs8.Opts{
+		"default": nil,
+		"AES-128-CBC PBKDF2 SHA-1": {
+			Cipher:  pkcs8.AES128CBC,
+			KDFOpts: pkcs8.PBKDF2Opts{SaltSize: 8, IterationCount: 2048, HMACHash: crypto.SHA1},
+		},
+		"3DES PBKDF2 SHA-256": {
+			Cipher:  pkcs8.TripleDESCBC,
+			KDFOpts: pkcs8.PBKDF2Opts{SaltSize: 8, IterationCount: 2048, HMACHash: crypto.SHA256},
+		},
+		"AES-256-CBC scrypt": {
+			Cipher:  pkcs8.AES256CBC,
+			KDFOpts: pkcs8.ScryptOpts{SaltSize: 16, CostParameter: 1 << 10, BlockSize: 8, ParallelizationParameter: 1},
+		},
+	}
+	formats := []PrivateKeyFormat{PrivateKeyFormatPKCS8, PrivateKeyFormatTraditional}
+	for keyName, key := range testPrivateKeys() {
+		for optsName, opts := range options {
+			encrypted := encryptPrivateKey(t, key, testPassphrase, opts)
+			for _, format := range formats {
+				t.Run(fmt.Sprintf("%s/%s/%s", keyName, optsName, format), func(t *testing.T) {
+					out, err := DecryptPrivateKey(encrypted, []byte(testPassphrase), format)
+					if err != nil {
+						t.Fatalf("DecryptPrivateKey: %v", err)
+					}
+					block, rest := pem.Decode(out)
+					if block == nil || len(rest) > 0 {
+						t.Fatalf("DecryptPrivateKey returned %q, want a single PEM block", out)
+					}
+					var decrypted any
+					switch block.Type {
+					case pemTypePrivateKey:
+						decrypted, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+					case pemTypeRSAPrivateKey:
+						decrypted, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+					case pemTypeECPrivateKey:
+						decrypted, err = x509.ParseECPrivateKey(block.Bytes)
+					default:
+						t.Fatalf("DecryptPrivateKey returned a %q PEM block", block.Type)
+					}
+					if err != nil {
+						t.Fatalf("parsing %s block: %v", block.Type, err)
+					}
+					if want := wantPrivateKeyPEMType(key, format); block.Type != want {
+						t.Errorf("PEM block type = %q, want %q", block.Type, want)
+					}
+					if !key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(decrypted) {
+						t.Error("decrypted private key differs from the encrypted one")
+					}
+				})
+			}
+		}
+	}
+}
+
+// wantPrivateKeyPEMType returns the type of the PEM block DecryptPrivateKey
+// encodes the supplied key in with the supplied format.
+func wantPrivateKeyPEMType(key crypto.Signer, format PrivateKeyFormat) string {
+	if format != PrivateKeyFormatTraditional {
+		return pemTypePrivateKey
+	}
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		return pemTypeRSAPrivateKey
+	}
+	return pemTypeECPrivateKey
+}
+
+func FuzzDecryptPrivateKey(f *testing.F) {
+	for _, tt := range decryptErrorTests(f) {
+		f.Add(tt.data, []byte(tt.passphrase), false)
+	}
+	for _, name := range []string{"RSA 1024", "EC P-256", "EC P-521"} {
+		encrypted := encryptPrivateKey(f, testPrivateKeys()[name], testPassphrase, nil)
+		f.Add(encrypted, []byte(testPassphrase), false)
+		f.Add(encrypted, []byte(testPassphrase), true)
+	}
+	f.Fuzz(func(t *testing.T, data []byte, passphrase []byte, traditional bool) {
+		format := PrivateKeyFormatPKCS8
+		if traditional {
+			format = PrivateKeyFormatTraditional
+		}
+		out, err := DecryptPrivateKey(data, passphrase, format)
+		if err != nil {
+			if out != nil {
+				t.Fatalf("DecryptPrivateKey returned %q with error %v", out, err)
+			}
+			return
+		}
+		key, err := parsePrivateKey(out)
+		if err != nil {
+			t.Fatalf("DecryptPrivateKey returned an unparseable private key %q: %v", out, err)
+		}
+		if err := checkPrivateKey(key); err != nil {
+			t.Fatalf("DecryptPrivateKey returned an unsupported private key: %v", err)
+		}
+	})
+}
+
+func TestJoinPEM(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{name: "both empty", a: "", b: "", want: ""},
+		{name: "empty chain", a: "cert\n", b: "", want: "cert\n"},
+		{name: "empty certificate", a: "", b: "chain\n", want: "chain\n"},
+		{name: "certificate with trailing newline", a: "cert\n", b: "chain\n", want: "cert\nchain\n"},
+		{name: "certificate without trailing newline", a: "cert", b: "chain\n", want: "cert\nchain\n"},
+		{name: "certificate without trailing newline and empty chain", a: "cert", b: "", want: "cert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinPEM(tt.a, tt.b); got != tt.want {
+				t.Errorf("joinPEM(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzJoinPEM(f *testing.F) {
+	f.Add([]byte("certificate"), []byte("chain"), false)
+	f.Add([]byte("certificate"), []byte("chain"), true)
+	f.Add([]byte{}, []byte{}, true)
+	f.Add(bytes.Repeat([]byte{0xff}, 100), []byte{0x30, 0x00}, true)
+	f.Fuzz(func(t *testing.T, certificate []byte, chain []byte, trimNewline bool) {
+		a := string(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: certificate}))
+		if trimNewline {
+			a = strings.TrimSuffix(a, "\n")
+		}
+		b := string(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: chain}))
+
+		got := joinPEM(a, b)
+		if !strings.HasPrefix(got, a) || !strings.HasSuffix(got, b) {
+			t.Fatalf("joinPEM(%q, %q) = %q, want a followed by b", a, b, got)
+		}
+		if extra := len(got) - len(a) - len(b); extra != 0 && !(extra == 1 && trimNewline) {
+			t.Fatalf("joinPEM(%q, %q) = %q, want only a newline between them", a, b, got)
+		}
+		first, rest := pem.Decode([]byte(got))
+		if first == nil || !bytes.Equal(first.Bytes, certificate) {
+			t.Fatalf("first PEM block of %q is %v, want the certificate", got, first)
+		}
+		second, rest := pem.Decode(rest)
+		if second == nil || !bytes.Equal(second.Bytes, chain) || len(rest) > 0 {
+			t.Fatalf("second PEM block of %q is %v, want the chain", got, second)
+		}
+		if joinPEM(got, "") != got || joinPEM("", got) != got {
+			t.Fatalf("joinPEM with an empty string changed %q", got)
+		}
+	})
+}
+
+func TestNormalizeKeyAlgorithm(t *testing.T) {
+	for _, algorithm := range svcsdktypes.KeyAlgorithm("").Values() {
+		described := strings.ReplaceAll(string(algorithm), "_", "-")
+		if got := normalizeKeyAlgorithm(described); got != string(algorithm) {
+			t.Errorf("normalizeKeyAlgorithm(%q) = %q, want %q", described, got, algorithm)
+		}
+		if got := normalizeKeyAlgorithm(string(algorithm)); got != string(algorithm) {
+			t.Errorf("normalizeKeyAlgorithm(%q) = %q, want it unchanged", algorithm, got)
+		}
+	}
+}
+
+func FuzzNormalizeKeyAlgorithm(f *testing.F) {
+	for _, algorithm := range svcsdktypes.KeyAlgorithm("").Values() {
+		f.Add(string(algorithm))
+		f.Add(strings.ReplaceAll(string(algorithm), "_", "-"))
+	}
+	f.Add("")
+	f.Add("--")
+	f.Add("RSA-2048-")
+	f.Fuzz(func(t *testing.T, algorithm string) {
+		got := normalizeKeyAlgorithm(algorithm)
+		if len(got) != len(algorithm) {
+			t.Fatalf("normalizeKeyAlgorithm(%q) = %q, want the same length", algorithm, got)
+		}
+		for i := 0; i < len(algorithm); i++ {
+			want := algorithm[i]
+			if want == '-' {
+				want = '_'
+			}
+			if got[i] != want {
+				t.Fatalf("normalizeKeyAlgorithm(%q) = %q, want only dashes replaced", algorithm, got)
+			}
+		}
+		if again := normalizeKeyAlgorithm(got); again != got {
+			t.Fatalf("normalizeKeyAlgorithm(%q) = %q, want it idempotent", got, again)
+		}
+	})
+}
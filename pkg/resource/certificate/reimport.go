@@ -0,0 +1,191 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// setImportSourceSerial records the serial number of the certificate in the
+// Secret imported by the supplied resource in Status.ImportSourceSerial, so
+// that a renewed certificate is noticed and re-imported. Errors reading the
+// Secret are only logged: they must not prevent the resource from being
+// read, e.g. while it is deleted, and the serial is refreshed on the next
+// read.
+func (rm *resourceManager) setImportSourceSerial(
+	ctx context.Context,
+	r *resource,
+) {
+	rm.bindReconciler()
+	if !hasImportSource(r.ko) {
+		r.ko.Status.ImportSourceSerial = nil
+		r.ko.Status.ImportSourceResourceVersion = nil
+		return
+	}
+	rlog := ackrtlog.FromContext(ctx)
+	serial, resourceVersion, err := rm.readImportSourceSerial(ctx, r.ko)
+	if err != nil {
+		rlog.Debug("unable to read import source", "error", err)
+		return
+	}
+	r.ko.Status.ImportSourceSerial = &serial
+	r.ko.Status.ImportSourceResourceVersion = &resourceVersion
+}
+
+// readImportSourceSerial returns the serial number of the certificate in
+// the Secret imported by the supplied Certificate and the resourceVersion
+// of the Secret it was read from. Secrets are not cached, only their
+// metadata, so the Secret is read from the API server only when its
+// resourceVersion differs from Status.ImportSourceResourceVersion. A
+// resourceVersion is never shared by two Secrets, so a Certificate that
+// switches to another Secret reads it too.
+func (rm *resourceManager) readImportSourceSerial(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (serial string, resourceVersion string, err error) {
+	nn, err := rm.permittedImportSourceSecretName(ctx, ko)
+	if err != nil {
+		return "", "", err
+	}
+	if cache := rm.kube().cache; cache != nil &&
+		ko.Status.ImportSourceSerial != nil && ko.Status.ImportSourceResourceVersion != nil {
+		metadata := &metav1.PartialObjectMetadata{}
+		metadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := cache.Get(ctx, nn, metadata); err == nil &&
+			metadata.ResourceVersion == *ko.Status.ImportSourceResourceVersion {
+			return *ko.Status.ImportSourceSerial, metadata.ResourceVersion, nil
+		}
+	}
+	secret, err := rm.readImportSourceSecret(ctx, ko)
+	if err != nil {
+		return "", "", err
+	}
+	certs, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return "", "", fmt.Errorf("Secret %s: %s: %w", nn, corev1.TLSCertKey, err)
+	}
+	if len(certs) == 0 {
+		return "", "", fmt.Errorf("Secret %s: %s: no PEM encoded certificate found", nn, corev1.TLSCertKey)
+	}
+	return formatSerial(certs[0].SerialNumber), secret.ResourceVersion, nil
+}
+
+// compareImportSourceSerial adds a difference to the delta when the
+// certificate in the import source Secret is not the one imported in ACM.
+func compareImportSourceSerial(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	source := b.ko.Status.ImportSourceSerial
+	imported := b.ko.Status.Serial
+	if source == nil || imported == nil {
+		return
+	}
+	if normalizeSerial(*source) != normalizeSerial(*imported) {
+		// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+		// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+		delta.Add("Spec.Status.ImportSourceSerial", imported, source)
+	}
+}
+
+// reimportCertificate imports the certificate in the import source Secret
+// under the ARN of the supplied resource, replacing the certificate that was
+// previously imported there.
+func (rm *resourceManager) reimportCertificate(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.reimportCertificate")
+	defer func() { exit(err) }()
+
+	if latest.ko.Status.ACKResourceMetadata == nil || latest.ko.Status.ACKResourceMetadata.ARN == nil {
+		return nil, ackerr.NewTerminalError(errors.New("cannot re-import a certificate without an ARN"))
+	}
+	input, err := rm.newImportCertificateInput(ctx, desired)
+	if err != nil {
+		return nil, err
+	}
+	if err = rm.setImportCertificateInputFromTLSSecret(ctx, desired, input); err != nil {
+		return nil, err
+	}
+	if err = inspectImportCertificateInput(input, time.Now()); err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	req, err := importedPolicyRequest(input)
+	if err != nil {
+		return nil, ackerr.NewTerminalError(err)
+	}
+	if err = rm.checkCertificatePolicies(ctx, desired.ko.Namespace, req); err != nil {
+		return nil, err
+	}
+	ko := desired.ko.DeepCopy()
+	ko.Status = *latest.ko.Status.DeepCopy()
+	rm.setStatusDefaults(ko)
+	if err = rm.awaitRequestApproval(ctx, ko, req); err != nil {
+		return &resource{ko}, err
+	}
+	input.CertificateArn = (*string)(latest.ko.Status.ACKResourceMetadata.ARN)
+	// Tags cannot be set when re-importing a certificate; they are kept on
+	// the existing certificate and synced separately.
+	input.Tags = nil
+
+	_, err = rm.sdkapi.ImportCertificate(ctx, input)
+	rm.metrics.RecordAPICall("UPDATE", "ImportCertificate", err)
+	if err != nil {
+		return nil, err
+	}
+	rlog.Info(
+		"re-imported certificate from import source",
+		"arn", *input.CertificateArn,
+	)
+	if err = rm.reimportReplicas(ctx, desired, ko); err != nil {
+		return &resource{ko}, err
+	}
+	return &resource{ko}, nil
+}
+
+// formatSerial formats a certificate serial number the way ACM reports it,
+// as colon separated hexadecimal bytes.
+func formatSerial(serial *big.Int) string {
+	b := serial.Bytes()
+	parts := make([]string, len(b))
+	for i := range b {
+		parts[i] = fmt.Sprintf("%02x", b[i])
+	}
+	return strings.Join(parts, ":")
+}
+
+// normalizeSerial returns the supplied serial number without separators and
+// leading zeros, so that serial numbers formatted differently compare
+// equal.
+func normalizeSerial(serial string) string {
+	serial = strings.ToLower(strings.ReplaceAll(serial, ":", ""))
+	return strings.TrimLeft(serial, "0")
+}
@@ -0,0 +1,405 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	smithy "github.com/aws/smithy-go"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// replacementRequeueDelay is how long we wait before checking on a
+	// replacement certificate that is pending validation, or on retired
+	// certificates that are still in use when the resource is deleted.
+	replacementRequeueDelay = 30 * time.Second
+)
+
+var (
+	// replacementFieldPaths are the Spec fields that RequestCertificate
+	// accepts but UpdateCertificateOptions cannot change.
+	replacementFieldPaths = []string{
+		"Spec.DomainName",
+		"Spec.SubjectAlternativeNames",
+		"Spec.KeyAlgorithm",
+		"Spec.CertificateAuthorityARN",
+	}
+
+	errReplacementRejected = fmt.Errorf(
+		"DomainName, SubjectAlternativeNames, KeyAlgorithm and "+
+			"CertificateAuthorityARN cannot be updated on an existing "+
+			"certificate; set spec.replacementPolicy to %q to request a "+
+			"new certificate instead",
+		svcapitypes.ReplacementPolicyReplace,
+	)
+	errReplacementPendingValidation = errors.New(
+		"replacement certificate is pending validation",
+	)
+	errRetiredCertificatesInUse = errors.New(
+		"retired certificates are still in use",
+	)
+)
+
+// requiresReplacement returns true if the supplied delta contains a change
+// that can only be applied by requesting a new certificate.
+func requiresReplacement(delta *ackcompare.Delta) bool {
+	for _, path := range replacementFieldPaths {
+		if delta.DifferentAt(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// replacementEnabled returns true if the resource opted into replacing its
+// certificate when an immutable field changes.
+func replacementEnabled(r *resource) bool {
+	return r.ko.Spec.ReplacementPolicy != nil &&
+		*r.ko.Spec.ReplacementPolicy == svcapitypes.ReplacementPolicyReplace
+}
+
+// compareReplacementStatus forces an update while a replacement certificate
+// is pending or a replaced certificate still needs to be deleted, so that
+// sdkUpdate gets a chance to make progress on either.
+func compareReplacementStatus(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+	// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+	if a.ko.Status.ReplacementCertificateARN != nil {
+		delta.Add("Spec.Status.ReplacementCertificateARN", a.ko.Status.ReplacementCertificateARN, nil)
+	}
+	if len(a.ko.Status.RetiredCertificateARNs) > 0 {
+		delta.Add("Spec.Status.RetiredCertificateARNs", a.ko.Status.RetiredCertificateARNs, nil)
+	}
+}
+
+// replaceCertificate drives the replacement of the certificate when one of
+// the fields ACM cannot update has changed. It requests a new certificate
+// with the desired parameters, waits for it to be issued, and then switches
+// the resource's ARN over to it. The previous certificate is recorded in
+// Status.RetiredCertificateARNs and deleted once it is no longer in use.
+func (rm *resourceManager) replaceCertificate(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.replaceCertificate")
+	defer func() { exit(err) }()
+
+	ko := desired.ko.DeepCopy()
+	rm.setStatusDefaults(ko)
+	ko.Status.IssuedAt = latest.ko.Status.IssuedAt
+	ko.Status.Status = latest.ko.Status.Status
+	ko.Status.Serial = latest.ko.Status.Serial
+
+	if !requiresReplacement(delta) {
+		// The desired state matches the current certificate again, so a
+		// replacement requested earlier is no longer needed.
+		if ko.Status.ReplacementCertificateARN != nil {
+			rlog.Info(
+				"discarding replacement certificate that is no longer needed",
+				"arn", *ko.Status.ReplacementCertificateARN,
+			)
+			if err = rm.deleteCertificate(ctx, *ko.Status.ReplacementCertificateARN); err != nil {
+				return nil, err
+			}
+			clearReplacementStatus(ko)
+		}
+		return &resource{ko}, nil
+	}
+	if !replacementEnabled(desired) {
+		return nil, ackerr.NewTerminalError(errReplacementRejected)
+	}
+
+	if ko.Status.ReplacementCertificateARN != nil {
+		replacement, err := rm.readReplacementCertificate(ctx, ko)
+		switch {
+		case err == ackerr.NotFound:
+			rlog.Info(
+				"replacement certificate no longer exists",
+				"arn", *ko.Status.ReplacementCertificateARN,
+			)
+			clearReplacementStatus(ko)
+		case err != nil:
+			return nil, err
+		case requiresReplacement(newResourceDelta(desired, replacement)):
+			// The desired state changed again while the replacement was
+			// pending validation.
+			rlog.Info(
+				"discarding replacement certificate that no longer matches the desired state",
+				"arn", *ko.Status.ReplacementCertificateARN,
+			)
+			if err = rm.deleteCertificate(ctx, *ko.Status.ReplacementCertificateARN); err != nil {
+				return nil, err
+			}
+			clearReplacementStatus(ko)
+		default:
+			return rm.promoteReplacementCertificate(ctx, ko, replacement)
+		}
+	}
+
+	arn, err := rm.requestReplacementCertificate(ctx, desired, ko)
+	if err != nil {
+		return &resource{ko}, err
+	}
+	rlog.Info("requested replacement certificate", "arn", arn)
+	ko.Status.ReplacementCertificateARN = &arn
+	return &resource{ko}, ackrequeue.NeededAfter(errReplacementPendingValidation, replacementRequeueDelay)
+}
+
+// promoteReplacementCertificate switches the resource over to the
+// replacement certificate once it has been issued. Until then, the
+// replacement's domain validation records are surfaced in
+// Status.ReplacementDomainValidations.
+func (rm *resourceManager) promoteReplacementCertificate(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	replacement *resource,
+) (*resource, error) {
+	rlog := ackrtlog.FromContext(ctx)
+	replacementARN := *ko.Status.ReplacementCertificateARN
+	ko.Status.ReplacementDomainValidations = replacement.ko.Status.DomainValidations
+
+	status := ""
+	if replacement.ko.Status.Status != nil {
+		status = *replacement.ko.Status.Status
+	}
+	switch status {
+	case string(svcsdktypes.CertificateStatusIssued):
+	case string(svcsdktypes.CertificateStatusPendingValidation):
+		return &resource{ko}, ackrequeue.NeededAfter(errReplacementPendingValidation, replacementRequeueDelay)
+	default:
+		msg := fmt.Sprintf("replacement certificate %s has status %s", replacementARN, status)
+		if replacement.ko.Status.FailureReason != nil {
+			msg += ": " + *replacement.ko.Status.FailureReason
+		}
+		return &resource{ko}, ackerr.NewTerminalError(errors.New(msg))
+	}
+
+	if ko.Spec.ExportTo != nil {
+		if err := rm.exportCertificate(ctx, replacement); err != nil {
+			return &resource{ko}, err
+		}
+	}
+	if ko.Status.ACKResourceMetadata.ARN != nil {
+		ko.Status.RetiredCertificateARNs = append(
+			ko.Status.RetiredCertificateARNs,
+			aws.String(string(*ko.Status.ACKResourceMetadata.ARN)),
+		)
+	}
+	// The replicas have the domain names and key algorithm of the replaced
+	// certificate, so they are replaced too.
+	retireReplicas(ko)
+	arn := ackv1alpha1.AWSResourceName(replacementARN)
+	ko.Status.ACKResourceMetadata.ARN = &arn
+	ko.Status.DomainValidations = replacement.ko.Status.DomainValidations
+	ko.Status.IssuedAt = replacement.ko.Status.IssuedAt
+	ko.Status.Status = replacement.ko.Status.Status
+	ko.Status.Serial = replacement.ko.Status.Serial
+	clearReplacementStatus(ko)
+	rlog.Info("switched to replacement certificate", "arn", replacementARN)
+	return &resource{ko}, nil
+}
+
+// readReplacementCertificate returns the observed state of the replacement
+// certificate recorded in the supplied object's status.
+func (rm *resourceManager) readReplacementCertificate(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (*resource, error) {
+	rko := ko.DeepCopy()
+	arn := ackv1alpha1.AWSResourceName(*ko.Status.ReplacementCertificateARN)
+	rko.Status.ACKResourceMetadata.ARN = &arn
+	return rm.sdkFind(ctx, &resource{rko})
+}
+
+// requestReplacementCertificate calls RequestCertificate with the desired
+// parameters once approved, recording the approval in the status of ko,
+// and returns the ARN of the new certificate. The idempotency token is
+// derived from the object's UID and generation so that a request retried
+// before the status could be saved does not create a second certificate.
+func (rm *resourceManager) requestReplacementCertificate(
+	ctx context.Context,
+	desired *resource,
+	ko *svcapitypes.Certificate,
+) (string, error) {
+	if err := validateCertificateRequest(desired); err != nil {
+		return "", err
+	}
+	req := requestedPolicyRequest(desired.ko)
+	if err := rm.checkCertificatePolicies(ctx, desired.ko.Namespace, req); err != nil {
+		return "", err
+	}
+	if err := rm.awaitRequestApproval(ctx, ko, req); err != nil {
+		return "", err
+	}
+	input, err := rm.newCreateRequestPayload(ctx, desired)
+	if err != nil {
+		return "", err
+	}
+	if err := rm.setRequestCertificateDefaults(ctx, desired, input); err != nil {
+		return "", err
+	}
+	input.IdempotencyToken = aws.String(replacementIdempotencyToken(desired.ko))
+
+	resp, err := rm.sdkapi.RequestCertificate(ctx, input)
+	rm.metrics.RecordAPICall("CREATE", "RequestCertificate", err)
+	if err != nil {
+		return "", err
+	}
+	return *resp.CertificateArn, nil
+}
+
+// replacementIdempotencyToken returns a RequestCertificate idempotency token
+// that is stable for a given generation of the supplied object.
+func replacementIdempotencyToken(ko *svcapitypes.Certificate) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", ko.UID, ko.Generation)))
+	// IdempotencyToken is limited to 32 characters
+	return hex.EncodeToString(sum[:16])
+}
+
+// deleteRetiredCertificates deletes the certificates in
+// Status.RetiredCertificateARNs that are no longer used by any AWS resource
+// and returns a copy of the resource with those certificates removed from
+// the list. Retired replicas are deleted in their own region.
+func (rm *resourceManager) deleteRetiredCertificates(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	rlog := ackrtlog.FromContext(ctx)
+	ko := r.ko.DeepCopy()
+	var remaining []*string
+	for _, arn := range ko.Status.RetiredCertificateARNs {
+		if arn == nil {
+			continue
+		}
+		resp, err := rm.certificateClient(*arn).DescribeCertificate(ctx, &svcsdk.DescribeCertificateInput{
+			CertificateArn: arn,
+		})
+		rm.metrics.RecordAPICall("READ_ONE", "DescribeCertificate", err)
+		if err != nil {
+			if awsErrorCode(err) == "ResourceNotFoundException" {
+				continue
+			}
+			return nil, err
+		}
+		if len(resp.Certificate.InUseBy) > 0 {
+			rlog.Debug("retired certificate is still in use", "arn", *arn, "in_use_by", resp.Certificate.InUseBy)
+			remaining = append(remaining, arn)
+			continue
+		}
+		if err = rm.deleteCertificate(ctx, *arn); err != nil {
+			if awsErrorCode(err) == "ResourceInUseException" {
+				remaining = append(remaining, arn)
+				continue
+			}
+			return nil, err
+		}
+		rlog.Info("deleted retired certificate", "arn", *arn)
+	}
+	ko.Status.RetiredCertificateARNs = remaining
+	return &resource{ko}, nil
+}
+
+// deleteReplacementCertificates deletes the pending replacement certificate
+// and any retired certificates of a resource that is being deleted. While a
+// retired certificate is still in use, the deletion is requeued so that the
+// finalizer keeps the resource, and with it the ARN of the certificate,
+// until the certificate can be deleted.
+func (rm *resourceManager) deleteReplacementCertificates(
+	ctx context.Context,
+	r *resource,
+) error {
+	rlog := ackrtlog.FromContext(ctx)
+	if r.ko.Status.ReplacementCertificateARN != nil {
+		if err := rm.deleteCertificate(ctx, *r.ko.Status.ReplacementCertificateARN); err != nil {
+			return err
+		}
+	}
+	inUse := []string{}
+	for _, arn := range r.ko.Status.RetiredCertificateARNs {
+		if arn == nil {
+			continue
+		}
+		if err := rm.deleteCertificate(ctx, *arn); err != nil {
+			if awsErrorCode(err) != "ResourceInUseException" {
+				return err
+			}
+			rlog.Debug("retired certificate is still in use", "arn", *arn)
+			inUse = append(inUse, *arn)
+		}
+	}
+	if len(inUse) > 0 {
+		return ackrequeue.NeededAfter(
+			fmt.Errorf("%w: %s", errRetiredCertificatesInUse, strings.Join(inUse, ", ")),
+			replacementRequeueDelay,
+		)
+	}
+	return nil
+}
+
+// deleteCertificate deletes the certificate with the supplied ARN, in the
+// region of the ARN. A certificate that does not exist anymore is not an
+// error.
+func (rm *resourceManager) deleteCertificate(
+	ctx context.Context,
+	arn string,
+) error {
+	_, err := rm.certificateClient(arn).DeleteCertificate(ctx, &svcsdk.DeleteCertificateInput{
+		CertificateArn: &arn,
+	})
+	rm.metrics.RecordAPICall("DELETE", "DeleteCertificate", err)
+	if err != nil && awsErrorCode(err) != "ResourceNotFoundException" {
+		return err
+	}
+	return nil
+}
+
+// clearReplacementStatus resets the status fields tracking a pending
+// replacement certificate.
+func clearReplacementStatus(ko *svcapitypes.Certificate) {
+	ko.Status.ReplacementCertificateARN = nil
+	ko.Status.ReplacementDomainValidations = nil
+}
+
+// awsErrorCode returns the error code of the supplied AWS API error, or an
+// empty string if err is not an AWS API error.
+func awsErrorCode(err error) string {
+	var awsErr smithy.APIError
+	if errors.As(err, &awsErr) {
+		return awsErr.ErrorCode()
+	}
+	return ""
+}
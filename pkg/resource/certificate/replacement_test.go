@@ -0,0 +1,106 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestPromoteReplacementCertificateRetiresReplicas(t *testing.T) {
+	ctx := context.Background()
+	rm, srv, _ := newTestResourceManager(t)
+	spec := svcapitypes.CertificateSpec{
+		DomainName:     aws.String("www.example.com"),
+		ReplicaRegions: aws.StringSlice([]string{"us-east-1"}),
+	}
+	latest := createCertificate(t, rm, spec)
+	oldARN := certificateARN(latest)
+	replicaARN := certificateARN(createCertificate(t, rm, spec))
+	spec.DomainName = aws.String("web.example.com")
+	replacementARN := certificateARN(createCertificate(t, rm, spec))
+	if err := srv.Issue(replacementARN); err != nil {
+		t.Fatal(err)
+	}
+
+	ko := latest.ko.DeepCopy()
+	ko.Spec = spec
+	ko.Status.Replicas = []*svcapitypes.CertificateReplica{{Region: "us-east-1", CertificateARN: replicaARN}}
+	ko.Status.ReplacementCertificateARN = aws.String(replacementARN)
+	replacement, err := rm.readReplacementCertificate(ctx, ko)
+	if err != nil {
+		t.Fatalf("readReplacementCertificate: %v", err)
+	}
+	promoted, err := rm.promoteReplacementCertificate(ctx, ko, replacement)
+	if err != nil {
+		t.Fatalf("promoteReplacementCertificate: %v", err)
+	}
+
+	if got := certificateARN(promoted); got != replacementARN {
+		t.Errorf("ARN = %s, want the replacement %s", got, replacementARN)
+	}
+	if promoted.ko.Status.Replicas != nil {
+		t.Errorf("Status.Replicas = %+v, want the replicas retired", promoted.ko.Status.Replicas)
+	}
+	if got, want := aws.ToStringSlice(promoted.ko.Status.RetiredCertificateARNs), []string{oldARN, replicaARN}; !slices.Equal(got, want) {
+		t.Errorf("Status.RetiredCertificateARNs = %q, want %q", got, want)
+	}
+	if !newResourceDelta(promoted, promoted).DifferentAt("Spec.Status.Replicas") {
+		t.Error("replicas of the replacement are not requested")
+	}
+
+	cleaned, err := rm.deleteRetiredCertificates(ctx, promoted)
+	if err != nil {
+		t.Fatalf("deleteRetiredCertificates: %v", err)
+	}
+	if len(cleaned.ko.Status.RetiredCertificateARNs) != 0 {
+		t.Errorf("Status.RetiredCertificateARNs = %q, want none", aws.ToStringSlice(cleaned.ko.Status.RetiredCertificateARNs))
+	}
+	for _, arn := range []string{oldARN, replicaARN} {
+		_, err := srv.Client().DescribeCertificate(ctx, &svcsdk.DescribeCertificateInput{CertificateArn: aws.String(arn)})
+		if awsErrorCode(err) != "ResourceNotFoundException" {
+			t.Errorf("describing retired certificate %s: %v, want it deleted", arn, err)
+		}
+	}
+}
+
+func TestCertificateClient(t *testing.T) {
+	rm, _, _ := newTestResourceManager(t)
+	tests := []struct {
+		name       string
+		arn        string
+		wantRegion string
+	}{
+		{name: "own region", arn: "arn:aws:acm:us-west-2:111122223333:certificate/a", wantRegion: "us-west-2"},
+		{name: "replica region", arn: "arn:aws:acm:us-east-1:111122223333:certificate/a", wantRegion: "us-east-1"},
+		{name: "not an ARN", arn: "certificate/a", wantRegion: "us-west-2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := rm.certificateClient(tt.arn)
+			if got := client.Options().Region; got != tt.wantRegion {
+				t.Errorf("region = %s, want %s", got, tt.wantRegion)
+			}
+			if (client == rm.sdkapi) != (tt.wantRegion == string(rm.awsRegion)) {
+				t.Errorf("client is the client of the resource manager: %v", client == rm.sdkapi)
+			}
+		})
+	}
+}
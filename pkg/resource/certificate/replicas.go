@@ -0,0 +1,446 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsarn "github.com/aws/aws-sdk-go-v2/aws/arn"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// replicaClient returns an ACM client for the supplied region, configured
+// like the client of the resource manager otherwise.
+func (rm *resourceManager) replicaClient(region string) *svcsdk.Client {
+	return svcsdk.NewFromConfig(rm.clientcfg, withDryRun, func(o *svcsdk.Options) {
+		o.Region = region
+	})
+}
+
+// certificateClient returns the ACM client for the region of the
+// certificate with the supplied ARN, which is the client of the resource
+// manager for its own region and a replica client otherwise.
+func (rm *resourceManager) certificateClient(arn string) *svcsdk.Client {
+	parsed, err := awsarn.Parse(arn)
+	if err != nil || parsed.Region == "" || parsed.Region == string(rm.awsRegion) {
+		return rm.sdkapi
+	}
+	return rm.replicaClient(parsed.Region)
+}
+
+// retireReplicas moves the replicas in the status of the supplied object to
+// Status.RetiredCertificateARNs, so that they are deleted once no longer in
+// use and replicas of the current certificate are created in their place.
+// It is called when the certificate of the resource is replaced, since the
+// replicas were requested with the parameters of the replaced certificate.
+func retireReplicas(ko *svcapitypes.Certificate) {
+	for _, replica := range ko.Status.Replicas {
+		ko.Status.RetiredCertificateARNs = append(ko.Status.RetiredCertificateARNs, aws.String(replica.CertificateARN))
+	}
+	ko.Status.Replicas = nil
+}
+
+// desiredReplicaRegions returns the regions in Spec.ReplicaRegions of the
+// supplied object, without duplicates.
+func desiredReplicaRegions(ko *svcapitypes.Certificate) []string {
+	regions := []string{}
+	for _, region := range ko.Spec.ReplicaRegions {
+		if region != nil && !slices.Contains(regions, *region) {
+			regions = append(regions, *region)
+		}
+	}
+	return regions
+}
+
+// pendingReplicaRegions returns the regions of the replicas in
+// Status.Replicas of the supplied object that are not issued yet.
+func pendingReplicaRegions(ko *svcapitypes.Certificate) []string {
+	regions := []string{}
+	for _, replica := range ko.Status.Replicas {
+		if replica.Status == nil || *replica.Status != string(svcsdktypes.CertificateStatusIssued) {
+			regions = append(regions, replica.Region)
+		}
+	}
+	return regions
+}
+
+// compareReplicas forces an update while Status.Replicas does not match
+// Spec.ReplicaRegions, or one of the replicas is not issued yet, so that
+// sdkUpdate gets a chance to create, delete or check on them.
+func compareReplicas(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	desired := desiredReplicaRegions(a.ko)
+	observed := []string{}
+	for _, replica := range b.ko.Status.Replicas {
+		observed = append(observed, replica.Region)
+	}
+	slices.Sort(desired)
+	slices.Sort(observed)
+	// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+	// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+	if !slices.Equal(desired, observed) || len(pendingReplicaRegions(b.ko)) > 0 {
+		delta.Add("Spec.Status.Replicas", desired, observed)
+	}
+}
+
+// readReplicas refreshes the status, failure reason and domain validation
+// records of the replicas in Status.Replicas of the supplied object.
+// Replicas that no longer exist are removed from the list so that they are
+// created again. Other errors are only logged: a region being unavailable
+// must not prevent the certificate from being read.
+func (rm *resourceManager) readReplicas(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) {
+	rlog := ackrtlog.FromContext(ctx)
+	replicas := []*svcapitypes.CertificateReplica{}
+	for _, replica := range ko.Status.Replicas {
+		resp, err := rm.replicaClient(replica.Region).DescribeCertificate(ctx, &svcsdk.DescribeCertificateInput{
+			CertificateArn: aws.String(replica.CertificateARN),
+		})
+		rm.metrics.RecordAPICall("READ_ONE", "DescribeCertificate", err)
+		if err != nil {
+			if awsErrorCode(err) == "ResourceNotFoundException" {
+				rlog.Info("replica certificate no longer exists", "region", replica.Region, "arn", replica.CertificateARN)
+				continue
+			}
+			rlog.Info("unable to read replica certificate", "region", replica.Region, "error", err)
+			replicas = append(replicas, replica)
+			continue
+		}
+		replicas = append(replicas, newCertificateReplica(replica.Region, replica.CertificateARN, resp.Certificate))
+	}
+	if len(replicas) == 0 {
+		replicas = nil
+	}
+	ko.Status.Replicas = replicas
+}
+
+// newCertificateReplica returns the status of the replica with the supplied
+// region and ARN described by detail.
+func newCertificateReplica(
+	region string,
+	arn string,
+	detail *svcsdktypes.CertificateDetail,
+) *svcapitypes.CertificateReplica {
+	replica := &svcapitypes.CertificateReplica{
+		Region:         region,
+		CertificateARN: arn,
+	}
+	if detail == nil {
+		return replica
+	}
+	if detail.Status != "" {
+		replica.Status = aws.String(string(detail.Status))
+	}
+	if detail.FailureReason != "" {
+		replica.FailureReason = aws.String(string(detail.FailureReason))
+	}
+	for _, dvo := range detail.DomainValidationOptions {
+		dv := &svcapitypes.DomainValidation{
+			DomainName:       dvo.DomainName,
+			ValidationDomain: dvo.ValidationDomain,
+		}
+		if dvo.ResourceRecord != nil {
+			dv.ResourceRecord = &svcapitypes.ResourceRecord{
+				Name:  dvo.ResourceRecord.Name,
+				Value: dvo.ResourceRecord.Value,
+			}
+			if dvo.ResourceRecord.Type != "" {
+				dv.ResourceRecord.Type = aws.String(string(dvo.ResourceRecord.Type))
+			}
+		}
+		if dvo.ValidationMethod != "" {
+			dv.ValidationMethod = aws.String(string(dvo.ValidationMethod))
+		}
+		if dvo.ValidationStatus != "" {
+			dv.ValidationStatus = aws.String(string(dvo.ValidationStatus))
+		}
+		replica.DomainValidations = append(replica.DomainValidations, dv)
+	}
+	return replica
+}
+
+// syncReplicas requests or imports a replica of the certificate in each
+// region of Spec.ReplicaRegions that does not have one yet, and deletes the
+// replicas in regions that were removed. The returned resource records the
+// replicas in Status.Replicas, including when an error is returned, so that
+// replicas created before the error are not lost.
+func (rm *resourceManager) syncReplicas(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncReplicas")
+	defer func() { exit(err) }()
+
+	ko := desired.ko.DeepCopy()
+	regions := desiredReplicaRegions(ko)
+	if slices.Contains(regions, string(rm.awsRegion)) {
+		return nil, ackerr.NewTerminalError(fmt.Errorf(
+			"replicaRegions cannot contain %s, the region of the certificate itself", rm.awsRegion,
+		))
+	}
+
+	replicas := []*svcapitypes.CertificateReplica{}
+	for i, replica := range latest.ko.Status.Replicas {
+		if slices.Contains(regions, replica.Region) {
+			replicas = append(replicas, replica)
+			continue
+		}
+		if err = rm.deleteReplica(ctx, replica); err != nil {
+			if awsErrorCode(err) != "ResourceInUseException" {
+				ko.Status.Replicas = append(replicas, latest.ko.Status.Replicas[i:]...)
+				return &resource{ko}, err
+			}
+			// The replica is deleted once it is no longer in use.
+			rlog.Info("replica certificate is still in use", "region", replica.Region, "arn", replica.CertificateARN)
+			replicas = append(replicas, replica)
+			err = nil
+			continue
+		}
+		rlog.Info("deleted replica certificate", "region", replica.Region, "arn", replica.CertificateARN)
+	}
+
+	for _, region := range regions {
+		if slices.ContainsFunc(replicas, func(r *svcapitypes.CertificateReplica) bool { return r.Region == region }) {
+			continue
+		}
+		var arn string
+		if isImportSpec(ko) {
+			arn, err = rm.importReplica(ctx, desired, ko, region, "")
+		} else {
+			arn, err = rm.requestReplica(ctx, desired, ko, region)
+		}
+		if err != nil {
+			ko.Status.Replicas = replicas
+			return &resource{ko}, err
+		}
+		rlog.Info("created replica certificate", "region", region, "arn", arn)
+		replicas = append(replicas, &svcapitypes.CertificateReplica{
+			Region:         region,
+			CertificateARN: arn,
+		})
+	}
+	if len(replicas) == 0 {
+		replicas = nil
+	}
+	ko.Status.Replicas = replicas
+	return &resource{ko}, nil
+}
+
+// replicasPendingError returns a requeue error naming the replicas of the
+// supplied object that are not issued yet, or nil if all of them are. The
+// error is surfaced in the ACK.ResourceSynced condition, so that the
+// Certificate only reports being synced once it is usable in every region.
+func replicasPendingError(ko *svcapitypes.Certificate) error {
+	pending := pendingReplicaRegions(ko)
+	if len(pending) == 0 {
+		return nil
+	}
+	return ackrequeue.NeededAfter(
+		fmt.Errorf("replica certificates are not issued yet in %s", strings.Join(pending, ", ")),
+		requeuePending,
+	)
+}
+
+// requestReplica calls RequestCertificate in the supplied region with the
+// desired parameters once approved, recording the approval in the status of
+// ko, and returns the ARN of the new certificate. The idempotency token is
+// derived from the object's UID and the region so that a request retried
+// before the status could be saved does not create a second certificate.
+func (rm *resourceManager) requestReplica(
+	ctx context.Context,
+	desired *resource,
+	ko *svcapitypes.Certificate,
+	region string,
+) (string, error) {
+	if err := validateCertificateRequest(desired); err != nil {
+		return "", err
+	}
+	req := requestedPolicyRequest(desired.ko)
+	if err := rm.checkCertificatePolicies(ctx, desired.ko.Namespace, req); err != nil {
+		return "", err
+	}
+	if err := rm.awaitRequestApproval(ctx, ko, req); err != nil {
+		return "", err
+	}
+	input, err := rm.newCreateRequestPayload(ctx, desired)
+	if err != nil {
+		return "", err
+	}
+	if err := rm.setRequestCertificateDefaults(ctx, desired, input); err != nil {
+		return "", err
+	}
+	input.IdempotencyToken = aws.String(replicaIdempotencyToken(desired.ko, region))
+
+	resp, err := rm.replicaClient(region).RequestCertificate(ctx, input)
+	rm.metrics.RecordAPICall("CREATE", "RequestCertificate", err)
+	if err != nil {
+		return "", err
+	}
+	return *resp.CertificateArn, nil
+}
+
+// importReplica imports the certificate of the supplied resource in the
+// supplied region once approved, recording the approval in the status of
+// ko, and returns the ARN of the imported certificate. When arn is not
+// empty, the certificate is re-imported under that ARN instead.
+func (rm *resourceManager) importReplica(
+	ctx context.Context,
+	desired *resource,
+	ko *svcapitypes.Certificate,
+	region string,
+	arn string,
+) (string, error) {
+	input, err := rm.newImportCertificateInput(ctx, desired)
+	if err != nil {
+		return "", err
+	}
+	if input.Tags, err = rm.sdkTags(ctx, desired); err != nil {
+		return "", err
+	}
+	if hasImportSource(desired.ko) {
+		if err = rm.setImportCertificateInputFromTLSSecret(ctx, desired, input); err != nil {
+			return "", err
+		}
+	}
+	if err = inspectImportCertificateInput(input, time.Now()); err != nil {
+		return "", ackerr.NewTerminalError(err)
+	}
+	req, err := importedPolicyRequest(input)
+	if err != nil {
+		return "", ackerr.NewTerminalError(err)
+	}
+	if err = rm.checkCertificatePolicies(ctx, desired.ko.Namespace, req); err != nil {
+		return "", err
+	}
+	if err = rm.awaitRequestApproval(ctx, ko, req); err != nil {
+		return "", err
+	}
+	// The ARN of the certificate in the region of the resource cannot be
+	// used in another region.
+	input.CertificateArn = nil
+	if arn != "" {
+		input.CertificateArn = aws.String(arn)
+		// Tags cannot be set when re-importing a certificate.
+		input.Tags = nil
+	}
+
+	resp, err := rm.replicaClient(region).ImportCertificate(ctx, input)
+	rm.metrics.RecordAPICall("CREATE", "ImportCertificate", err)
+	if err != nil {
+		return "", err
+	}
+	return *resp.CertificateArn, nil
+}
+
+// reimportReplicas re-imports the certificate of the supplied resource
+// under the ARN of each replica in the status of ko, after the certificate
+// in the import source was renewed.
+func (rm *resourceManager) reimportReplicas(
+	ctx context.Context,
+	desired *resource,
+	ko *svcapitypes.Certificate,
+) error {
+	rlog := ackrtlog.FromContext(ctx)
+	for _, replica := range ko.Status.Replicas {
+		if _, err := rm.importReplica(ctx, desired, ko, replica.Region, replica.CertificateARN); err != nil {
+			return err
+		}
+		rlog.Info("re-imported replica certificate", "region", replica.Region, "arn", replica.CertificateARN)
+	}
+	return nil
+}
+
+// syncReplicaTags applies the desired tags to every replica of the
+// supplied resource.
+func (rm *resourceManager) syncReplicaTags(
+	ctx context.Context,
+	latest *resource,
+	desiredTags []*svcapitypes.Tag,
+	latestTags []*svcapitypes.Tag,
+) error {
+	for _, replica := range latest.ko.Status.Replicas {
+		if err := syncTags(
+			ctx, rm.replicaClient(replica.Region), rm.metrics,
+			replica.CertificateARN,
+			desiredTags, latestTags,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteReplica deletes the supplied replica certificate. A replica that
+// does not exist anymore is not an error.
+func (rm *resourceManager) deleteReplica(
+	ctx context.Context,
+	replica *svcapitypes.CertificateReplica,
+) error {
+	_, err := rm.replicaClient(replica.Region).DeleteCertificate(ctx, &svcsdk.DeleteCertificateInput{
+		CertificateArn: aws.String(replica.CertificateARN),
+	})
+	rm.metrics.RecordAPICall("DELETE", "DeleteCertificate", err)
+	if err != nil && awsErrorCode(err) != "ResourceNotFoundException" {
+		return err
+	}
+	return nil
+}
+
+// deleteReplicas deletes the replicas of a resource that is being deleted.
+func (rm *resourceManager) deleteReplicas(
+	ctx context.Context,
+	r *resource,
+) error {
+	var errs []error
+	for _, replica := range r.ko.Status.Replicas {
+		errs = append(errs, rm.deleteReplica(ctx, replica))
+	}
+	return errors.Join(errs...)
+}
+
+// replicaIdempotencyToken returns a RequestCertificate idempotency token
+// that is stable for the replica of the current certificate of the supplied
+// object in a region. It differs once the certificate is replaced, so that
+// the replicas of the replacement are new certificates.
+func replicaIdempotencyToken(ko *svcapitypes.Certificate, region string) string {
+	arn := ""
+	if ko.Status.ACKResourceMetadata != nil && ko.Status.ACKResourceMetadata.ARN != nil {
+		arn = string(*ko.Status.ACKResourceMetadata.ARN)
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", ko.UID, region, arn)))
+	// IdempotencyToken is limited to 32 characters
+	return hex.EncodeToString(sum[:16])
+}
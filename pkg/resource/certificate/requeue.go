@@ -0,0 +1,157 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"time"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// requeuePending is how often a certificate is described while ACM is
+	// working on it: pending validation, being renewed or being replaced.
+	requeuePending = 1 * time.Minute
+	// requeueRenewal is how often a certificate is described while ACM is
+	// renewing it but nothing is expected to change within minutes.
+	requeueRenewal = 5 * time.Minute
+	// requeueSettled is how often a certificate is described once nothing
+	// is expected to change, for example once it is issued.
+	requeueSettled = 6 * time.Hour
+	// requeueExpiringWithin is how close to its NotAfter a certificate has
+	// to be before it is described more often than requeueSettled, so that
+	// a renewal, re-import or expiry is picked up promptly.
+	requeueExpiringWithin = 30 * 24 * time.Hour
+	// requeueExpiring is the shortest interval used for a certificate that
+	// is about to expire.
+	requeueExpiring = 10 * time.Minute
+)
+
+// requeueIntervals are the intervals a Certificate is synced again after,
+// depending on its state in ACM.
+type requeueIntervals struct {
+	pending time.Duration
+	renewal time.Duration
+	settled time.Duration
+}
+
+// certificateRequeueIntervals returns the requeue intervals of the
+// supplied Certificate: the defaults, overridden by its RequeuePolicy.
+func certificateRequeueIntervals(ko *svcapitypes.Certificate) requeueIntervals {
+	intervals := requeueIntervals{
+		pending: requeuePending,
+		renewal: requeueRenewal,
+		settled: requeueSettled,
+	}
+	policy := ko.Spec.RequeuePolicy
+	if policy == nil {
+		return intervals
+	}
+	if policy.Pending != nil && policy.Pending.Duration > 0 {
+		intervals.pending = policy.Pending.Duration
+	}
+	if policy.Renewal != nil && policy.Renewal.Duration > 0 {
+		intervals.renewal = policy.Renewal.Duration
+	}
+	if policy.Settled != nil && policy.Settled.Duration > 0 {
+		intervals.settled = policy.Settled.Duration
+	}
+	return intervals
+}
+
+// certificateRequeueAfter returns how long to wait before the supplied
+// Certificate is synced again, based on its state in ACM. A zero duration
+// means the state is not known and the default requeue interval applies.
+func certificateRequeueAfter(
+	ko *svcapitypes.Certificate,
+	now time.Time,
+) time.Duration {
+	if ko.Status.Status == nil {
+		return 0
+	}
+	intervals := certificateRequeueIntervals(ko)
+	if ko.Status.ReplacementCertificateARN != nil || len(pendingReplicaRegions(ko)) > 0 ||
+		unresolvedGatewayListeners(ko) {
+		return intervals.pending
+	}
+	switch svcapitypes.CertificateStatus_SDK(*ko.Status.Status) {
+	case svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION:
+		return intervals.pending
+	case svcapitypes.CertificateStatus_SDK_ISSUED:
+	default:
+		// EXPIRED, FAILED, INACTIVE, REVOKED and VALIDATION_TIMED_OUT are
+		// final; only a change to the spec, which triggers a sync on its
+		// own, can do something about them. A ValidationRetryPolicy acts
+		// in the sync that observes the failure, not after a requeue.
+		return intervals.settled
+	}
+	if renewal := ko.Status.RenewalSummary; renewal != nil && renewal.RenewalStatus != nil {
+		switch svcapitypes.RenewalStatus(*renewal.RenewalStatus) {
+		case svcapitypes.RenewalStatus_PENDING_VALIDATION:
+			return intervals.pending
+		case svcapitypes.RenewalStatus_PENDING_AUTO_RENEWAL:
+			return intervals.renewal
+		}
+	}
+	if ko.Status.NotAfter == nil {
+		return intervals.settled
+	}
+	return expiringRequeueAfter(intervals.settled, ko.Status.NotAfter.Time.Sub(now))
+}
+
+// expiringRequeueAfter returns the requeue interval of an issued
+// certificate that expires in the supplied duration. The interval shrinks
+// proportionally from the supplied settled interval as the expiry gets
+// closer than requeueExpiringWithin, down to requeueExpiring.
+func expiringRequeueAfter(settled time.Duration, untilExpiry time.Duration) time.Duration {
+	if untilExpiry >= requeueExpiringWithin {
+		return settled
+	}
+	d := time.Duration(float64(settled) * float64(untilExpiry) / float64(requeueExpiringWithin))
+	if d < requeueExpiring {
+		return min(settled, requeueExpiring)
+	}
+	return d
+}
+
+// lateInitializeAndRequeue completes late initialization of latest from
+// observed like the generated LateInitialize does, and schedules the next
+// sync of the resource after requeueAfter instead of the fixed interval the
+// runtime uses for synced resources.
+func (rm *resourceManager) lateInitializeAndRequeue(
+	observed acktypes.AWSResource,
+	latest acktypes.AWSResource,
+	requeueAfter time.Duration,
+) (acktypes.AWSResource, error) {
+	res := rm.lateInitializeFromReadOneOutput(observed, latest)
+	if rm.incompleteLateInitialization(res) {
+		msg := "Late initialization did not complete, requeuing with delay of 5 seconds"
+		reason := "Delayed Late Initialization"
+		ackcondition.SetLateInitialized(res, corev1.ConditionFalse, &msg, &reason)
+		ackcondition.SetSynced(res, corev1.ConditionFalse, nil, nil)
+		return res, ackrequeue.NeededAfter(nil, 5*time.Second)
+	}
+	msg := "Late initialization successful"
+	reason := "Late initialization successful"
+	ackcondition.SetLateInitialized(res, corev1.ConditionTrue, &msg, &reason)
+	// Returning a requeue error makes the runtime skip setting the Synced
+	// condition, so it is set here the way it would be for a nil error.
+	ackcondition.SetSynced(res, corev1.ConditionTrue, &ackcondition.SyncedMessage, nil)
+	return res, ackrequeue.NeededAfter(nil, requeueAfter)
+}
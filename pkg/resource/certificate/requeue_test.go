@@ -0,0 +1,70 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestCertificateRequeueAfterGatewayListeners(t *testing.T) {
+	listener := func(resolved string, reason string) *svcapitypes.CertificateGatewayListener {
+		return &svcapitypes.CertificateGatewayListener{
+			Namespace:    "gateways",
+			Gateway:      "gw",
+			Listener:     "https",
+			ResolvedRefs: resolved,
+			Reason:       reason,
+		}
+	}
+	tests := []struct {
+		name      string
+		listeners []*svcapitypes.CertificateGatewayListener
+		want      time.Duration
+	}{
+		{name: "no listeners", want: requeueSettled},
+		{
+			name:      "resolved listener",
+			listeners: []*svcapitypes.CertificateGatewayListener{listener("True", gatewayReasonResolvedRefs)},
+			want:      requeueSettled,
+		},
+		{
+			name:      "reference not permitted",
+			listeners: []*svcapitypes.CertificateGatewayListener{listener("False", gatewayReasonRefNotPermitted)},
+			want:      requeuePending,
+		},
+		{
+			name: "reference removed",
+			listeners: []*svcapitypes.CertificateGatewayListener{
+				listener("True", gatewayReasonResolvedRefs),
+				listener("False", gatewayReasonRefRemoved),
+			},
+			want: requeuePending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := newTestCertificate(svcapitypes.CertificateSpec{}).ko
+			ko.Status.Status = aws.String(string(svcapitypes.CertificateStatus_SDK_ISSUED))
+			ko.Status.GatewayListeners = tt.listeners
+			if got := certificateRequeueAfter(ko, time.Now()); got != tt.want {
+				t.Errorf("certificateRequeueAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -126,13 +126,16 @@ func (rm *resourceManager) sdkFind(
 	} else {
 		ko.Status.DomainValidations = nil
 	}
-	ko.Spec.Tags, err = listTags(
-		ctx, rm.sdkapi, rm.metrics,
+	observedTags, err := listTags(
+		ctx, rm.tagsClient(), rm.metrics,
 		string(*r.ko.Status.ACKResourceMetadata.ARN),
 	)
 	if err != nil {
 		return nil, err
 	}
+	if ko.Spec.Tags, err = rm.specTags(ctx, r, observedTags); err != nil {
+		return nil, err
+	}
 
 	if ko.Status.ACKResourceMetadata == nil {
 		ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{}
@@ -246,6 +249,9 @@ func (rm *resourceManager) sdkFind(
 		if resp.Certificate.Options.CertificateTransparencyLoggingPreference != "" {
 			f15.CertificateTransparencyLoggingPreference = aws.String(string(resp.Certificate.Options.CertificateTransparencyLoggingPreference))
 		}
+		if resp.Certificate.Options.Export != "" {
+			f15.Export = aws.String(string(resp.Certificate.Options.Export))
+		}
 		ko.Spec.Options = f15
 	} else {
 		ko.Spec.Options = nil
@@ -348,6 +354,11 @@ func (rm *resourceManager) sdkFind(
 	}
 
 	rm.setStatusDefaults(ko)
+	rm.setImportSourceSerial(ctx, &resource{ko})
+	rm.readReplicas(ctx, ko)
+	rm.readGatewayListeners(ctx, ko)
+	ko.Status.DryRunPlan = nil
+	setValidationExpiresAt(ko)
 	return &resource{ko}, nil
 }
 
@@ -387,40 +398,35 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	if isDryRun(ctx, desired) {
+		return rm.planCreate(ctx, desired)
+	}
+	if desired, err = rm.applyCertificateClass(ctx, desired); err != nil {
+		return nil, err
+	}
 	created, isImport, err := rm.maybeImportCertificate(ctx, desired)
 	if err != nil {
-		return nil, err
+		return created, err
 	}
 	if isImport {
 		return created, nil
 	}
-	if err = validatePublicValidationOptions(desired); err != nil {
-		return nil, ackerr.NewTerminalError(err)
+	if err = validateCertificateRequest(desired); err != nil {
+		return nil, err
+	}
+	if err = rm.checkCertificatePolicies(ctx, desired.ko.Namespace, requestedPolicyRequest(desired.ko)); err != nil {
+		return nil, err
+	}
+	if desired, err = rm.awaitApproval(ctx, desired, requestedPolicyRequest(desired.ko)); err != nil {
+		return desired, err
 	}
 
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
 	}
-	// We only support DNS-based validation, because
-	// certificate renewal is not really automatable when email verification
-	// is used.
-	//
-	// See discussion here:
-	// https://docs.aws.amazon.com/acm/latest/userguide/email-validation.html
-	//
-	// Unfortunately, because fields in the "ignore" configuration list are
-	// now deleted from the aws-sdk-go private/model/api.Shape object,
-	// setting `override_values` does not work.
-
-	input.ValidationMethod = "DNS"
-
-	// NOTE: exportPreference can ONLY be set for public certificates
-	if desired.ko.Spec.ExportTo != nil && desired.ko.Spec.CertificateAuthorityARN == nil && desired.ko.Spec.CertificateAuthorityRef == nil {
-		if input.Options == nil {
-			input.Options = &svcsdktypes.CertificateOptions{}
-		}
-		input.Options.Export = "ENABLED"
+	if err := rm.setRequestCertificateDefaults(ctx, desired, input); err != nil {
+		return nil, err
 	}
 
 	var resp *svcsdk.RequestCertificateOutput
@@ -482,6 +488,9 @@ func (rm *resourceManager) newCreateRequestPayload(
 		if r.ko.Spec.Options.CertificateTransparencyLoggingPreference != nil {
 			f4.CertificateTransparencyLoggingPreference = svcsdktypes.CertificateTransparencyLoggingPreference(*r.ko.Spec.Options.CertificateTransparencyLoggingPreference)
 		}
+		if r.ko.Spec.Options.Export != nil {
+			f4.Export = svcsdktypes.CertificateExport(*r.ko.Spec.Options.Export)
+		}
 		res.Options = f4
 	}
 	if r.ko.Spec.SubjectAlternativeNames != nil {
@@ -518,6 +527,32 @@ func (rm *resourceManager) sdkUpdate(
 	defer func() {
 		exit(err)
 	}()
+	if isDryRun(ctx, desired) {
+		return rm.planUpdate(ctx, desired, latest, delta)
+	}
+	if delta.DifferentAt("Spec.Status.AnnotatedConsumers") {
+		// Consumers and Gateway listeners are patched first and their
+		// errors returned last, so that one that does not exist yet does not
+		// hold up the other updates.
+		var consumersErr error
+		if desired, consumersErr = rm.syncConsumers(ctx, desired, latest); consumersErr != nil {
+			defer func() {
+				if err == nil {
+					err = consumersErr
+				}
+			}()
+		}
+	}
+	if delta.DifferentAt("Spec.Status.GatewayListeners") {
+		var gatewaysErr error
+		if desired, gatewaysErr = rm.syncGatewayListeners(ctx, desired, latest); gatewaysErr != nil {
+			defer func() {
+				if err == nil {
+					err = gatewaysErr
+				}
+			}()
+		}
+	}
 	if delta.DifferentAt("Spec.Status.IssuedAt") {
 		rlog.Info("Exporting certificate due to IssuedAt change")
 		if err = rm.exportCertificate(ctx, &resource{latest.ko}); err != nil {
@@ -553,23 +588,49 @@ func (rm *resourceManager) sdkUpdate(
 	}
 
 	if delta.DifferentAt("Spec.Tags") {
+		desiredTags, latestTags, err := rm.awsTagSets(ctx, desired, latest)
+		if err != nil {
+			return nil, err
+		}
 		if err := syncTags(
-			ctx, rm.sdkapi, rm.metrics,
+			ctx, rm.tagsClient(), rm.metrics,
 			string(*desired.ko.Status.ACKResourceMetadata.ARN),
-			desired.ko.Spec.Tags, latest.ko.Spec.Tags,
+			desiredTags, latestTags,
 		); err != nil {
 			return nil, err
 		}
+		if err := rm.syncReplicaTags(ctx, latest, desiredTags, latestTags); err != nil {
+			return nil, err
+		}
 	}
-	if !delta.DifferentExcept("Spec.Tags") {
-		return desired, nil
+	if delta.DifferentAt("Spec.Status.RetiredCertificateARNs") {
+		if desired, err = rm.deleteRetiredCertificates(ctx, desired); err != nil {
+			return nil, err
+		}
+	}
+	if delta.DifferentAt("Spec.Status.ValidationRetries") {
+		return rm.retryValidation(ctx, desired, latest)
+	}
+	if delta.DifferentAt("Spec.Status.Replicas") {
+		if desired, err = rm.syncReplicas(ctx, desired, latest); err != nil {
+			return desired, err
+		}
+	}
+	if !delta.DifferentExcept("Spec.Tags", "Spec.Status.RetiredCertificateARNs", "Spec.Status.Replicas", "Spec.Status.AnnotatedConsumers", "Spec.Status.GatewayListeners") {
+		return desired, replicasPendingError(desired.ko)
 	}
 	if latest.ko.Status.Type != nil && *latest.ko.Status.Type == string(svcapitypes.CertificateType_IMPORTED) {
+		if delta.DifferentAt("Spec.Status.ImportSourceSerial") {
+			return rm.reimportCertificate(ctx, desired, latest)
+		}
 		if delta.DifferentAt("Spec.Options") {
 			return nil, ackerr.NewTerminalError(errors.New("only tags can be updated for an imported certificate"))
 		}
 		return desired, nil
 	}
+	if requiresReplacement(delta) || delta.DifferentAt("Spec.Status.ReplacementCertificateARN") {
+		return rm.replaceCertificate(ctx, desired, latest, delta)
+	}
 
 	input, err := rm.newUpdateRequestPayload(ctx, desired, delta)
 	if err != nil {
@@ -611,6 +672,9 @@ func (rm *resourceManager) newUpdateRequestPayload(
 		if r.ko.Spec.Options.CertificateTransparencyLoggingPreference != nil {
 			f1.CertificateTransparencyLoggingPreference = svcsdktypes.CertificateTransparencyLoggingPreference(*r.ko.Spec.Options.CertificateTransparencyLoggingPreference)
 		}
+		if r.ko.Spec.Options.Export != nil {
+			f1.Export = svcsdktypes.CertificateExport(*r.ko.Spec.Options.Export)
+		}
 		res.Options = f1
 	}
 
@@ -627,6 +691,22 @@ func (rm *resourceManager) sdkDelete(
 	defer func() {
 		exit(err)
 	}()
+	if isDryRun(ctx, r) {
+		return rm.planDelete(ctx, r)
+	}
+	if err = rm.releaseConsumers(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.releaseGatewayListeners(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.deleteReplacementCertificates(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.deleteReplicas(ctx, r); err != nil {
+		return nil, err
+	}
+
 	input, err := rm.newDeleteRequestPayload(r)
 	if err != nil {
 		return nil, err
@@ -812,6 +892,9 @@ func (rm *resourceManager) newImportCertificateInput(
 	}
 
 	{
+		if err := rm.checkSecretReferenceGrant(ctx, r.ko, r.ko.Spec.PrivateKey, SecretGrantAccessRead); err != nil {
+			return nil, err
+		}
 		tmpSecret, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.PrivateKey)
 		if err != nil {
 			return nil, ackrequeue.Needed(err)
@@ -822,6 +905,9 @@ func (rm *resourceManager) newImportCertificateInput(
 	}
 
 	{
+		if err := rm.checkSecretReferenceGrant(ctx, r.ko, r.ko.Spec.Certificate, SecretGrantAccessRead); err != nil {
+			return nil, err
+		}
 		tmpSecret, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.Certificate)
 		if err != nil {
 			return nil, ackrequeue.Needed(err)
@@ -832,6 +918,9 @@ func (rm *resourceManager) newImportCertificateInput(
 	}
 
 	{
+		if err := rm.checkSecretReferenceGrant(ctx, r.ko, r.ko.Spec.CertificateChain, SecretGrantAccessRead); err != nil {
+			return nil, err
+		}
 		tmpSecret, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.CertificateChain)
 		if err != nil {
 			return nil, ackrequeue.Needed(err)
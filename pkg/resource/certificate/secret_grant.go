@@ -0,0 +1,198 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlrtcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrlrtlog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=acm.services.k8s.aws,resources=certificatesecretgrants,verbs=list;watch
+
+const (
+	// secretGrantControllerName is the name of the controller that syncs
+	// the Certificates a CertificateSecretGrant applies to when the grant
+	// changes.
+	secretGrantControllerName = "certificate-secret-grant"
+
+	// SecretGrantAccessRead lets Certificates import from the Secrets of a
+	// CertificateSecretGrant.
+	SecretGrantAccessRead = "Read"
+	// SecretGrantAccessWrite lets Certificates export into the Secrets of a
+	// CertificateSecretGrant.
+	SecretGrantAccessWrite = "Write"
+	// SecretGrantAccessReadWrite lets Certificates both import from and
+	// export into the Secrets of a CertificateSecretGrant.
+	SecretGrantAccessReadWrite = "ReadWrite"
+)
+
+// checkSecretGrant returns a Terminal error if the Secret with the supplied
+// namespace and name is in another namespace than the supplied Certificate
+// and no CertificateSecretGrant in its namespace permits the supplied access
+// on behalf of the Certificate. An empty namespace is the namespace of the
+// Certificate, and an empty name is any Secret of a grant. The
+// secretGrantControllerName controller syncs the Certificate again once a
+// grant is created or changed.
+func (rm *resourceManager) checkSecretGrant(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	namespace string,
+	name string,
+	access string,
+) error {
+	if namespace == "" || namespace == ko.Namespace {
+		return nil
+	}
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return err
+	}
+	grants := &svcapitypes.CertificateSecretGrantList{}
+	if err := kc.List(ctx, grants, client.InNamespace(namespace)); err != nil {
+		return fmt.Errorf("listing CertificateSecretGrants in namespace %s: %w", namespace, err)
+	}
+	for i := range grants.Items {
+		if secretGrantPermits(&grants.Items[i].Spec, ko.Namespace, name, access) {
+			return nil
+		}
+	}
+	verb := "read"
+	if access == SecretGrantAccessWrite {
+		verb = "write"
+	}
+	secret := "Secret " + name
+	if name == "" {
+		secret = "its Secrets"
+	}
+	return ackerr.NewTerminalError(fmt.Errorf(
+		"no CertificateSecretGrant in namespace %s permits Certificates in namespace %s to %s %s",
+		namespace, ko.Namespace, verb, secret,
+	))
+}
+
+// checkSecretReferenceGrant is checkSecretGrant for a Secret referenced by
+// the supplied SecretKeyReference, which may be nil.
+func (rm *resourceManager) checkSecretReferenceGrant(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+	ref *ackv1alpha1.SecretKeyReference,
+	access string,
+) error {
+	if ref == nil {
+		return nil
+	}
+	return rm.checkSecretGrant(ctx, ko, ref.Namespace, ref.Name, access)
+}
+
+// secretGrantPermits returns true if the supplied grant permits the supplied
+// access to the named Secret, or to any of its Secrets if name is empty, for
+// Certificates in the supplied namespace.
+func secretGrantPermits(
+	grant *svcapitypes.CertificateSecretGrantSpec,
+	namespace string,
+	name string,
+	access string,
+) bool {
+	if !slices.Contains(grant.Namespaces, namespace) {
+		return false
+	}
+	if name != "" && len(grant.SecretNames) > 0 && !slices.Contains(grant.SecretNames, name) {
+		return false
+	}
+	return grant.Access == access || grant.Access == SecretGrantAccessReadWrite
+}
+
+// watchSecretGrants adds a controller to the supplied controller manager
+// that syncs the Certificates that are in a Terminal condition in the
+// namespaces a CertificateSecretGrant lists once the grant is created or
+// changed, so that a Certificate that was missing the grant does not wait
+// for a change to its spec. Grants are watched with the supplied cache,
+// which the Certificates are listed from, and Certificates are read with
+// the supplied reader.
+func watchSecretGrants(
+	mgr ctrlrt.Manager,
+	cache ctrlrtcache.Cache,
+	reader client.Reader,
+) error {
+	started := time.Now()
+	return ctrlrt.NewControllerManagedBy(mgr).
+		Named(secretGrantControllerName).
+		WatchesRawSource(source.Kind(
+			cache,
+			&svcapitypes.CertificateSecretGrant{},
+			handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, grant *svcapitypes.CertificateSecretGrant) []reconcile.Request {
+				return terminalCertificatesGranted(ctx, cache, grant)
+			}),
+			predicate.TypedFuncs[*svcapitypes.CertificateSecretGrant]{
+				// The grants that exist when the controller starts are read
+				// by the initial sync of the Certificates.
+				CreateFunc: func(e event.TypedCreateEvent[*svcapitypes.CertificateSecretGrant]) bool {
+					return e.Object.GetCreationTimestamp().After(started)
+				},
+				DeleteFunc: func(event.TypedDeleteEvent[*svcapitypes.CertificateSecretGrant]) bool {
+					return false
+				},
+				GenericFunc: func(event.TypedGenericEvent[*svcapitypes.CertificateSecretGrant]) bool {
+					return false
+				},
+			},
+		)).
+		Complete(&certificateReconciler{reader: reader})
+}
+
+// terminalCertificatesGranted returns a request for each Certificate in a
+// namespace the supplied grant lists that is in a Terminal condition.
+func terminalCertificatesGranted(
+	ctx context.Context,
+	reader client.Reader,
+	grant *svcapitypes.CertificateSecretGrant,
+) []reconcile.Request {
+	var requests []reconcile.Request
+	for _, namespace := range grant.Spec.Namespaces {
+		list := &svcapitypes.CertificateList{}
+		if err := reader.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			ctrlrtlog.FromContext(ctx).Error(err, "unable to list certificates granted access to secrets",
+				"namespace", grant.Namespace, "name", grant.Name)
+			continue
+		}
+		for i := range list.Items {
+			terminal := ackcondition.Terminal(&resource{&list.Items[i]})
+			if terminal == nil || terminal.Status != corev1.ConditionTrue {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&list.Items[i]),
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,99 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestSecretGrantPermits(t *testing.T) {
+	grant := &svcapitypes.CertificateSecretGrantSpec{
+		Namespaces:  []string{testNamespace},
+		SecretNames: []string{"tls"},
+		Access:      SecretGrantAccessRead,
+	}
+	tests := []struct {
+		name      string
+		namespace string
+		secret    string
+		access    string
+		want      bool
+	}{
+		{name: "listed Secret", namespace: testNamespace, secret: "tls", access: SecretGrantAccessRead, want: true},
+		{name: "any Secret", namespace: testNamespace, access: SecretGrantAccessRead, want: true},
+		{name: "unlisted Secret", namespace: testNamespace, secret: "other", access: SecretGrantAccessRead},
+		{name: "unlisted namespace", namespace: "other", secret: "tls", access: SecretGrantAccessRead},
+		{name: "other access", namespace: testNamespace, secret: "tls", access: SecretGrantAccessWrite},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := secretGrantPermits(grant, tt.namespace, tt.secret, tt.access); got != tt.want {
+				t.Errorf("secretGrantPermits() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCertManagerCertificateNeedsGrant(t *testing.T) {
+	rm, _, _ := newTestResourceManager(t)
+	ko := newTestCertificate(svcapitypes.CertificateSpec{
+		CertManagerCertificateRef: &svcapitypes.CertManagerCertificateReference{Name: "web", Namespace: "issuers"},
+	}).ko
+	// The fake client does not know cert-manager Certificates, so reading
+	// one fails with a retryable error.
+	_, err := rm.permittedImportSourceSecretName(context.Background(), ko)
+	if !isTerminal(err) {
+		t.Fatalf("permittedImportSourceSecretName error = %v, want a terminal error for the missing grant", err)
+	}
+}
+
+func TestTerminalCertificatesGranted(t *testing.T) {
+	certificate := func(namespace string, name string, terminal corev1.ConditionStatus) *svcapitypes.Certificate {
+		ko := &svcapitypes.Certificate{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		if terminal != "" {
+			ko.Status.Conditions = []*ackv1alpha1.Condition{{Type: ackv1alpha1.ConditionTypeTerminal, Status: terminal}}
+		}
+		return ko
+	}
+	kc := newFakeKubeClient(t,
+		certificate(testNamespace, "terminal", corev1.ConditionTrue),
+		certificate(testNamespace, "recovered", corev1.ConditionFalse),
+		certificate(testNamespace, "synced", ""),
+		certificate("other", "terminal", corev1.ConditionTrue),
+		certificate("ungranted", "terminal", corev1.ConditionTrue),
+	)
+	grant := &svcapitypes.CertificateSecretGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "secrets"},
+		Spec: svcapitypes.CertificateSecretGrantSpec{
+			Namespaces: []string{testNamespace, "other"},
+			Access:     SecretGrantAccessRead,
+		},
+	}
+	var got []string
+	for _, req := range terminalCertificatesGranted(context.Background(), kc, grant) {
+		got = append(got, req.NamespacedName.String())
+	}
+	slices.Sort(got)
+	if want := []string{testNamespace + "/terminal", "other/terminal"}; !slices.Equal(got, want) {
+		t.Errorf("terminalCertificatesGranted() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/aws-controllers-k8s/acm-controller/pkg/tags"
+)
+
+const (
+	flagTagsCacheRefreshInterval    = "tags-cache-refresh-interval"
+	defaultTagsCacheRefreshInterval = 10 * time.Minute
+)
+
+var (
+	// tagsCache holds the tags of the certificates managed by all resource
+	// managers, so that they are not listed from ACM on every sync.
+	tagsCache = tags.NewCache()
+	// tagsCacheRefreshInterval is how long cached tags are used before they
+	// are listed from ACM again, to pick up changes made outside of the
+	// controller.
+	tagsCacheRefreshInterval = defaultTagsCacheRefreshInterval
+)
+
+func init() {
+	flag.DurationVar(
+		&tagsCacheRefreshInterval, flagTagsCacheRefreshInterval,
+		defaultTagsCacheRefreshInterval,
+		"How long the tags of a certificate listed from ACM are cached. "+
+			"Tags changed by the controller are always listed again. "+
+			"Set to 0 to disable caching.",
+	)
+}
+
+// tagsClient returns the client used to list and change the tags of
+// certificates.
+func (rm *resourceManager) tagsClient() *tags.CachingClient {
+	return tags.NewCachingClient(rm.sdkapi, tagsCache, tagsCacheRefreshInterval)
+}
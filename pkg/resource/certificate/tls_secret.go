@@ -0,0 +1,188 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// tlsSecretCAKey is the key of the optional CA certificate in a
+// kubernetes.io/tls Secret, as written by cert-manager.
+const tlsSecretCAKey = "ca.crt"
+
+// hasImportSource returns true if the supplied Certificate imports the
+// contents of a kubernetes.io/tls Secret, either referenced directly or
+// written by cert-manager.
+func hasImportSource(ko *svcapitypes.Certificate) bool {
+	return ko.Spec.ImportFrom != nil || ko.Spec.CertManagerCertificateRef != nil
+}
+
+// importSourceSecretName returns the namespace and name of the
+// kubernetes.io/tls Secret imported by the supplied Certificate.
+func importSourceSecretName(
+	ctx context.Context,
+	kc client.Client,
+	ko *svcapitypes.Certificate,
+) (types.NamespacedName, error) {
+	if ref := ko.Spec.CertManagerCertificateRef; ref != nil {
+		return certManagerCertificateSecretName(ctx, kc, ko.Namespace, ref)
+	}
+	return importFromSecretName(ko), nil
+}
+
+// importFromSecretName returns the namespace and name of the Secret
+// referenced by the ImportFrom of the supplied Certificate.
+func importFromSecretName(ko *svcapitypes.Certificate) types.NamespacedName {
+	nn := types.NamespacedName{
+		Namespace: ko.Spec.ImportFrom.Namespace,
+		Name:      ko.Spec.ImportFrom.Name,
+	}
+	if nn.Namespace == "" {
+		nn.Namespace = ko.Namespace
+	}
+	return nn
+}
+
+// permittedImportSourceSecretName returns the namespace and name of the
+// kubernetes.io/tls Secret imported by the supplied Certificate, once a
+// CertificateSecretGrant permits reading it.
+func (rm *resourceManager) permittedImportSourceSecretName(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (types.NamespacedName, error) {
+	kc, err := rm.kubeClient()
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+	// The cert-manager Certificate names the Secret, so it is only read
+	// once a grant permits reading Secrets of its namespace.
+	if ref := ko.Spec.CertManagerCertificateRef; ref != nil {
+		namespace := certManagerCertificateName(ko.Namespace, ref).Namespace
+		if err := rm.checkSecretGrant(ctx, ko, namespace, "", SecretGrantAccessRead); err != nil {
+			return types.NamespacedName{}, err
+		}
+	}
+	nn, err := importSourceSecretName(ctx, kc, ko)
+	if err != nil {
+		return types.NamespacedName{}, err
+	}
+	if err := rm.checkSecretGrant(ctx, ko, nn.Namespace, nn.Name, SecretGrantAccessRead); err != nil {
+		return types.NamespacedName{}, err
+	}
+	return nn, nil
+}
+
+// readImportSourceSecret returns the kubernetes.io/tls Secret imported by
+// the supplied Certificate, once it holds a certificate and a private key.
+// An Opaque Secret with the same keys is accepted too.
+func (rm *resourceManager) readImportSourceSecret(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (*corev1.Secret, error) {
+	nn, err := rm.permittedImportSourceSecretName(ctx, ko)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{}
+	if err := rm.kube().apiReader.Get(ctx, nn, secret); err != nil {
+		return nil, ackrequeue.Needed(fmt.Errorf("reading Secret %s: %w", nn, err))
+	}
+	if secret.Type != corev1.SecretTypeTLS && secret.Type != corev1.SecretTypeOpaque {
+		return nil, ackerr.NewTerminalError(fmt.Errorf(
+			"Secret %s has type %s, expected %s or %s",
+			nn, secret.Type, corev1.SecretTypeTLS, corev1.SecretTypeOpaque,
+		))
+	}
+	// Issuers such as cert-manager create the Secret before filling it in,
+	// so missing keys are waited for rather than reported as terminal.
+	for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+		if len(secret.Data[key]) == 0 {
+			return nil, ackrequeue.Needed(fmt.Errorf("Secret %s has no %s key", nn, key))
+		}
+	}
+	return secret, nil
+}
+
+// setImportCertificateInputFromTLSSecret sets the certificate, private key
+// and certificate chain of the supplied input from the kubernetes.io/tls
+// Secret imported by the supplied resource.
+func (rm *resourceManager) setImportCertificateInputFromTLSSecret(
+	ctx context.Context,
+	r *resource,
+	input *svcsdk.ImportCertificateInput,
+) error {
+	secret, err := rm.readImportSourceSecret(ctx, r.ko)
+	if err != nil {
+		return err
+	}
+	certificate, chain, err := splitTLSSecretCertificates(
+		secret.Data[corev1.TLSCertKey],
+		secret.Data[tlsSecretCAKey],
+	)
+	if err != nil {
+		return ackerr.NewTerminalError(fmt.Errorf(
+			"Secret %s/%s: %w", secret.Namespace, secret.Name, err,
+		))
+	}
+	input.Certificate = certificate
+	input.CertificateChain = chain
+	input.PrivateKey = secret.Data[corev1.TLSPrivateKeyKey]
+	return nil
+}
+
+// splitTLSSecretCertificates splits the PEM encoded tls.crt of a
+// kubernetes.io/tls Secret into the leaf certificate and the chain that
+// follows it, and adds the certificates of ca.crt to the chain unless they
+// are already part of it.
+func splitTLSSecretCertificates(
+	tlsCrt []byte,
+	caCrt []byte,
+) (certificate []byte, chain []byte, err error) {
+	certs, err := parseCertificates(tlsCrt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", corev1.TLSCertKey, err)
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("%s: no PEM encoded certificate found", corev1.TLSCertKey)
+	}
+	cas, err := parseCertificates(caCrt)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", tlsSecretCAKey, err)
+	}
+	rest := certs[1:]
+	for _, ca := range cas {
+		found := false
+		for _, cert := range certs {
+			if bytes.Equal(cert.Raw, ca.Raw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rest = append(rest, ca)
+		}
+	}
+	return encodeCertificates(certs[:1]), encodeCertificates(rest), nil
+}
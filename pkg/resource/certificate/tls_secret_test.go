@@ -0,0 +1,150 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/elliptic"
+	"crypto/x509"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func TestReadImportSourceSecret(t *testing.T) {
+	certPEM, keyPEM := selfSignedCertificate(t, "imported.example.org", time.Now().Add(24*time.Hour))
+	tests := []struct {
+		name         string
+		secretType   corev1.SecretType
+		data         map[string]string
+		wantTerminal string
+		wantErr      bool
+	}{
+		{
+			name:       "kubernetes.io/tls",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string]string{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		},
+		{
+			name:       "Opaque",
+			secretType: corev1.SecretTypeOpaque,
+			data:       map[string]string{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+		},
+		{
+			name:         "other type",
+			secretType:   corev1.SecretTypeDockerConfigJson,
+			data:         map[string]string{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM},
+			wantTerminal: "expected kubernetes.io/tls or Opaque",
+		},
+		{
+			name:       "private key not written yet",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string]string{corev1.TLSCertKey: certPEM},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "tls", Namespace: testNamespace},
+				Type:       tt.secretType,
+			}
+			secret.Data = map[string][]byte{}
+			for key, value := range tt.data {
+				secret.Data[key] = []byte(value)
+			}
+			rm, _, _ := newTestResourceManager(t)
+			kc := newFakeKubeClient(t, secret)
+			bindKubeClients(t, kubeClients{client: kc, cache: kc, apiReader: kc})
+			ko := newTestCertificate(svcapitypes.CertificateSpec{
+				ImportFrom: &svcapitypes.TLSSecretReference{Name: "tls"},
+			}).ko
+
+			_, err := rm.readImportSourceSecret(context.Background(), ko)
+			switch {
+			case tt.wantTerminal != "":
+				if !isTerminal(err) || !strings.Contains(err.Error(), tt.wantTerminal) {
+					t.Fatalf("readImportSourceSecret error = %v, want a terminal error containing %q", err, tt.wantTerminal)
+				}
+			case tt.wantErr:
+				if err == nil || isTerminal(err) {
+					t.Fatalf("readImportSourceSecret error = %v, want a retryable error", err)
+				}
+			case err != nil:
+				t.Fatalf("readImportSourceSecret: %v", err)
+			}
+		})
+	}
+}
+
+func TestSplitTLSSecretCertificates(t *testing.T) {
+	now := time.Now().Add(-time.Hour)
+	root := issueTestCertificate(t, "root", newECKey(t, elliptic.P256()), nil, now)
+	intermediate := issueTestCertificate(t, "intermediate", newECKey(t, elliptic.P256()), root, now)
+	leaf := issueTestCertificate(t, "leaf", newECKey(t, elliptic.P256()), intermediate, now)
+	encode := func(issuers ...*testIssuer) string {
+		certs := []*x509.Certificate{}
+		for _, issuer := range issuers {
+			certs = append(certs, issuer.cert)
+		}
+		return string(encodeCertificates(certs))
+	}
+
+	tests := []struct {
+		name          string
+		tlsCrt        string
+		caCrt         string
+		wantChain     []string
+		wantErrPrefix string
+	}{
+		{name: "certificate only", tlsCrt: encode(leaf), wantChain: []string{}},
+		{name: "certificate and chain", tlsCrt: encode(leaf, intermediate), wantChain: []string{"intermediate"}},
+		{name: "CA added to chain", tlsCrt: encode(leaf, intermediate), caCrt: encode(root), wantChain: []string{"intermediate", "root"}},
+		{name: "CA already in chain", tlsCrt: encode(leaf, intermediate, root), caCrt: encode(root), wantChain: []string{"intermediate", "root"}},
+		{name: "CA of the certificate", tlsCrt: encode(leaf), caCrt: encode(intermediate), wantChain: []string{"intermediate"}},
+		{name: "no certificate", tlsCrt: "", wantErrPrefix: "tls.crt: no PEM encoded certificate found"},
+		{name: "invalid certificate", tlsCrt: "not PEM", wantErrPrefix: "tls.crt: "},
+		{name: "invalid CA", tlsCrt: encode(leaf), caCrt: "not PEM", wantErrPrefix: "ca.crt: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certificate, chain, err := splitTLSSecretCertificates([]byte(tt.tlsCrt), []byte(tt.caCrt))
+			if tt.wantErrPrefix != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErrPrefix) {
+					t.Fatalf("splitTLSSecretCertificates error = %v, want one starting with %q", err, tt.wantErrPrefix)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitTLSSecretCertificates: %v", err)
+			}
+			if got := string(certificate); got != encode(leaf) {
+				t.Errorf("certificate = %s, want the leaf certificate", got)
+			}
+			certs, err := parseCertificates(chain)
+			if err != nil {
+				t.Fatalf("parsing chain: %v", err)
+			}
+			if got := subjects(certs); !slices.Equal(got, tt.wantChain) {
+				t.Errorf("chain = %q, want %q", got, tt.wantChain)
+			}
+		})
+	}
+}
@@ -0,0 +1,531 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// DNS validation only works for up to 5 chained CNAME records
+	limitDomainValidationOptionsPublic = 5
+	// The maximum number of domain names, including the DomainName, that can
+	// be added to an ACM certificate. The initial quota is lower and can be
+	// raised up to this value.
+	limitSubjectAlternativeNames = 100
+	// The maximum length of the DomainName, which ACM puts in the Common
+	// Name of the certificate.
+	maxLengthDomainName = 64
+	// The maximum length of a SubjectAlternativeName.
+	maxLengthSubjectAlternativeName = 253
+)
+
+var (
+	specPath = field.NewPath("spec")
+
+	// domainLabelRegexp matches a single label of a domain name.
+	domainLabelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+	// regionRegexp matches the name of an AWS region, such as us-east-1 or
+	// us-gov-west-1.
+	regionRegexp = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+	// requestKeyAlgorithms are the key algorithms RequestCertificate accepts.
+	// The other KeyAlgorithm values are for imported certificates only.
+	requestKeyAlgorithms = []string{
+		string(svcapitypes.KeyAlgorithm_RSA_2048),
+		string(svcapitypes.KeyAlgorithm_EC_prime256v1),
+		string(svcapitypes.KeyAlgorithm_EC_secp384r1),
+	}
+)
+
+// validateCertificate checks the supplied Certificate for spec combinations
+// that ACM would reject or that the controller cannot act on. When old is
+// not nil, ko is an update of old and only the fields that changed are
+// checked, so that objects created before a rule existed and fields filled
+// in by late initialization are not rejected.
+func validateCertificate(
+	old *svcapitypes.Certificate,
+	ko *svcapitypes.Certificate,
+) field.ErrorList {
+	errs := field.ErrorList{}
+	if old == nil {
+		errs = append(errs, validateImportOrRequest(ko)...)
+	}
+	errs = append(errs, validateExportTo(old, ko)...)
+	// The export preference is late initialized, possibly for private
+	// certificates too, so on update it is only checked when it changes
+	// from one value to another.
+	if old == nil || (exportPreference(old) != nil && !equality.Semantic.DeepEqual(exportPreference(old), exportPreference(ko))) {
+		errs = append(errs, validateOptionsExport(ko)...)
+	}
+	if old == nil || !equality.Semantic.DeepEqual(old.Spec.ReplicaRegions, ko.Spec.ReplicaRegions) {
+		errs = append(errs, validateReplicaRegions(ko)...)
+	}
+	if old == nil || !equality.Semantic.DeepEqual(old.Spec.ValidationRetryPolicy, ko.Spec.ValidationRetryPolicy) {
+		errs = append(errs, validateValidationRetryPolicy(ko)...)
+	}
+	if old == nil || !equality.Semantic.DeepEqual(old.Spec.Consumers, ko.Spec.Consumers) {
+		errs = append(errs, validateConsumers(ko)...)
+	}
+	if isImportSpec(ko) {
+		return errs
+	}
+	if old == nil {
+		return append(errs, validateRequest(ko)...)
+	}
+	errs = append(errs, validateReplacedFields(old, ko)...)
+	if !equality.Semantic.DeepEqual(old.Spec.KeyAlgorithm, ko.Spec.KeyAlgorithm) {
+		errs = append(errs, validateRequestKeyAlgorithm(ko)...)
+	}
+	// DomainValidationOptions are only sent to ACM when a certificate is
+	// requested, and are late initialized from the issued certificate
+	// afterwards, so they are only checked when the domain names change and
+	// the certificate will be requested again.
+	if !equality.Semantic.DeepEqual(old.Spec.DomainName, ko.Spec.DomainName) ||
+		!equality.Semantic.DeepEqual(old.Spec.SubjectAlternativeNames, ko.Spec.SubjectAlternativeNames) {
+		errs = append(errs, validateDomainNames(ko)...)
+		errs = append(errs, validateDomainValidationOptions(ko)...)
+	}
+	return errs
+}
+
+// validateRequest checks the fields of the supplied Certificate that are
+// sent to ACM when a certificate is requested.
+func validateRequest(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	errs = append(errs, validateRequestKeyAlgorithm(ko)...)
+	errs = append(errs, validateDomainNames(ko)...)
+	errs = append(errs, validateDomainValidationOptions(ko)...)
+	return errs
+}
+
+// validateReplacedFields checks that the fields of a requested certificate
+// that ACM cannot update, and that the controller replaces the certificate
+// for, only change when the updated Certificate has a ReplacementPolicy of
+// Replace. Like the immutability rules of other fields, a field that was
+// not set before may be set, which is how late initialization fills in
+// KeyAlgorithm and SubjectAlternativeNames. Certificates that were not
+// requested yet may change freely.
+func validateReplacedFields(
+	old *svcapitypes.Certificate,
+	ko *svcapitypes.Certificate,
+) field.ErrorList {
+	errs := field.ErrorList{}
+	requested := old.Status.ACKResourceMetadata != nil && old.Status.ACKResourceMetadata.ARN != nil
+	if !requested || replacementEnabled(&resource{ko}) {
+		return errs
+	}
+	msg := fmt.Sprintf(
+		"cannot be changed on an existing certificate unless spec.replacementPolicy is %s",
+		svcapitypes.ReplacementPolicyReplace,
+	)
+	if old.Spec.DomainName != nil && !equality.Semantic.DeepEqual(old.Spec.DomainName, ko.Spec.DomainName) {
+		errs = append(errs, field.Forbidden(specPath.Child("domainName"), msg))
+	} else if len(old.Spec.SubjectAlternativeNames) > 0 &&
+		!maps.Equal(subjectAlternativeNameSet(&resource{old}), subjectAlternativeNameSet(&resource{ko})) {
+		errs = append(errs, field.Forbidden(specPath.Child("subjectAlternativeNames"), msg))
+	}
+	if old.Spec.KeyAlgorithm != nil && (ko.Spec.KeyAlgorithm == nil ||
+		normalizeKeyAlgorithm(*old.Spec.KeyAlgorithm) != normalizeKeyAlgorithm(*ko.Spec.KeyAlgorithm)) {
+		errs = append(errs, field.Forbidden(specPath.Child("keyAlgorithm"), msg))
+	}
+	if old.Spec.CertificateAuthorityARN != nil &&
+		!equality.Semantic.DeepEqual(old.Spec.CertificateAuthorityARN, ko.Spec.CertificateAuthorityARN) {
+		errs = append(errs, field.Forbidden(specPath.Child("certificateAuthorityARN"), msg))
+	}
+	return errs
+}
+
+// isImportSpec returns true if the supplied Certificate imports a
+// certificate rather than requesting one from ACM.
+func isImportSpec(ko *svcapitypes.Certificate) bool {
+	return ko.Spec.Certificate != nil || hasImportSource(ko)
+}
+
+// isPublicRequestSpec returns true if the supplied Certificate requests a
+// public certificate, i.e. neither imports one nor names a private CA.
+func isPublicRequestSpec(ko *svcapitypes.Certificate) bool {
+	return !isImportSpec(ko) &&
+		ko.Spec.CertificateAuthorityARN == nil &&
+		ko.Spec.CertificateAuthorityRef == nil
+}
+
+// validateImportOrRequest checks that the supplied Certificate either
+// imports a certificate or requests one, but does not mix the fields of the
+// two.
+func validateImportOrRequest(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	spec := ko.Spec
+	if isImportSpec(ko) {
+		const msg = "cannot be set when importing a certificate"
+		if spec.DomainName != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("domainName"), msg))
+		}
+		if len(spec.SubjectAlternativeNames) > 0 {
+			errs = append(errs, field.Forbidden(specPath.Child("subjectAlternativeNames"), msg))
+		}
+		if len(spec.DomainValidationOptions) > 0 {
+			errs = append(errs, field.Forbidden(specPath.Child("domainValidationOptions"), msg))
+		}
+		if spec.KeyAlgorithm != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("keyAlgorithm"), msg))
+		}
+		if spec.Options != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("options"), msg))
+		}
+		if spec.CertificateAuthorityARN != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("certificateAuthorityARN"), msg))
+		}
+		if spec.CertificateAuthorityRef != nil {
+			errs = append(errs, field.Forbidden(specPath.Child("certificateAuthorityRef"), msg))
+		}
+		if hasImportSource(ko) {
+			const msg = "cannot be set when importing from a Secret"
+			if spec.ImportFrom != nil && spec.CertManagerCertificateRef != nil {
+				errs = append(errs, field.Forbidden(
+					specPath.Child("certManagerCertificateRef"),
+					"cannot be set together with importFrom",
+				))
+			}
+			if spec.Certificate != nil {
+				errs = append(errs, field.Forbidden(specPath.Child("certificate"), msg))
+			}
+			if spec.PrivateKey != nil {
+				errs = append(errs, field.Forbidden(specPath.Child("privateKey"), msg))
+			}
+			if spec.CertificateChain != nil {
+				errs = append(errs, field.Forbidden(specPath.Child("certificateChain"), msg))
+			}
+		} else if spec.PrivateKey == nil {
+			errs = append(errs, field.Required(specPath.Child("privateKey"), "required when importing a certificate"))
+		}
+		return errs
+	}
+	const msg = "can only be set when importing a certificate"
+	if spec.PrivateKey != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("privateKey"), msg))
+	}
+	if spec.CertificateChain != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("certificateChain"), msg))
+	}
+	if spec.CertificateARN != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("certificateARN"), msg))
+	}
+	if spec.DomainName == nil {
+		errs = append(errs, field.Required(specPath.Child("domainName"), "required when requesting a certificate"))
+	}
+	return errs
+}
+
+// validateExportTo checks that the certificate referenced by the supplied
+// Certificate can be exported. ACM cannot export imported certificates, and
+// public certificates can only be exported if they are exportable, which
+// the controller requests by default when ExportTo is set at the time the
+// certificate is requested.
+func validateExportTo(
+	old *svcapitypes.Certificate,
+	ko *svcapitypes.Certificate,
+) field.ErrorList {
+	errs := field.ErrorList{}
+	if ko.Spec.ExportTo == nil {
+		return errs
+	}
+	path := specPath.Child("exportTo")
+	if isImportSpec(ko) {
+		errs = append(errs, field.Forbidden(path, "imported certificates cannot be exported"))
+		return errs
+	}
+	if !isPublicRequestSpec(ko) {
+		return errs
+	}
+	export := aws.ToString(exportPreference(ko))
+	switch {
+	case export == string(svcapitypes.CertificateExport_DISABLED):
+		errs = append(errs, field.Forbidden(
+			path,
+			"cannot be set when options.export is DISABLED, since ACM only "+
+				"exports public certificates that are exportable",
+		))
+	case old != nil && old.Spec.ExportTo == nil && export != string(svcapitypes.CertificateExport_ENABLED):
+		errs = append(errs, field.Forbidden(
+			path,
+			"can only be set on an existing public certificate with options.export "+
+				"set to ENABLED, since ACM only exports public certificates that are "+
+				"exportable",
+		))
+	}
+	return errs
+}
+
+// exportPreference returns Options.Export of the supplied Certificate, or nil
+// if it is not set.
+func exportPreference(ko *svcapitypes.Certificate) *string {
+	if ko.Spec.Options == nil {
+		return nil
+	}
+	return ko.Spec.Options.Export
+}
+
+// validateOptionsExport checks the export preference of the supplied
+// Certificate. Only public certificates have one: private certificates can
+// always be exported, and imported certificates never can.
+func validateOptionsExport(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	export := exportPreference(ko)
+	if export == nil {
+		return errs
+	}
+	path := specPath.Child("options", "export")
+	switch *export {
+	case string(svcapitypes.CertificateExport_ENABLED), string(svcapitypes.CertificateExport_DISABLED):
+	default:
+		errs = append(errs, field.NotSupported(path, *export, []string{
+			string(svcapitypes.CertificateExport_ENABLED),
+			string(svcapitypes.CertificateExport_DISABLED),
+		}))
+	}
+	if !isImportSpec(ko) && !isPublicRequestSpec(ko) {
+		errs = append(errs, field.Forbidden(path, "can only be set for public certificates"))
+	}
+	return errs
+}
+
+// validateReplicaRegions checks that the ReplicaRegions of the supplied
+// Certificate are distinct region names, and that the certificate is not
+// issued by a private CA, which can only issue certificates in its own
+// region.
+func validateReplicaRegions(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(ko.Spec.ReplicaRegions) == 0 {
+		return errs
+	}
+	path := specPath.Child("replicaRegions")
+	if ko.Spec.CertificateAuthorityARN != nil || ko.Spec.CertificateAuthorityRef != nil {
+		errs = append(errs, field.Forbidden(path, "cannot be set for certificates issued by a private certificate authority"))
+	}
+	seen := map[string]bool{}
+	for i, region := range ko.Spec.ReplicaRegions {
+		switch {
+		case region == nil:
+			errs = append(errs, field.Required(path.Index(i), ""))
+		case !regionRegexp.MatchString(*region):
+			errs = append(errs, field.Invalid(path.Index(i), *region, "must be an AWS region, as in us-east-1"))
+		case seen[*region]:
+			errs = append(errs, field.Duplicate(path.Index(i), *region))
+		default:
+			seen[*region] = true
+		}
+	}
+	return errs
+}
+
+// validateConsumers checks that the Consumers of the supplied Certificate
+// are distinct Ingresses and Services.
+func validateConsumers(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	path := specPath.Child("consumers")
+	seen := map[svcapitypes.CertificateConsumer]bool{}
+	for i, consumer := range ko.Spec.Consumers {
+		if consumer == nil {
+			errs = append(errs, field.Required(path.Index(i), ""))
+			continue
+		}
+		if _, ok := consumerAnnotations[consumer.Kind]; !ok {
+			errs = append(errs, field.NotSupported(
+				path.Index(i).Child("kind"), consumer.Kind,
+				[]string{ConsumerKindIngress, ConsumerKindService},
+			))
+		}
+		if consumer.Name == "" {
+			errs = append(errs, field.Required(path.Index(i).Child("name"), ""))
+		}
+		if seen[*consumer] {
+			errs = append(errs, field.Duplicate(path.Index(i), consumer.Kind+" "+consumer.Name))
+		}
+		seen[*consumer] = true
+	}
+	return errs
+}
+
+// validateValidationRetryPolicy checks that the ValidationRetryPolicy of the
+// supplied Certificate is set on a requested certificate, and only lists
+// failure reasons ACM reports.
+func validateValidationRetryPolicy(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	policy := ko.Spec.ValidationRetryPolicy
+	if policy == nil {
+		return errs
+	}
+	path := specPath.Child("validationRetryPolicy")
+	if isImportSpec(ko) {
+		errs = append(errs, field.Forbidden(path, "imported certificates are not validated by ACM"))
+		return errs
+	}
+	if policy.MaxAttempts < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxAttempts"), policy.MaxAttempts, "must be at least 1"))
+	}
+	reasons := []string{}
+	for _, reason := range svcsdktypes.FailureReason("").Values() {
+		reasons = append(reasons, string(reason))
+	}
+	for i, reason := range policy.FailureReasons {
+		if !slices.Contains(reasons, reason) {
+			errs = append(errs, field.NotSupported(path.Child("failureReasons").Index(i), reason, reasons))
+		}
+	}
+	return errs
+}
+
+// validateRequestKeyAlgorithm checks that the KeyAlgorithm of the supplied
+// Certificate can be used to request a certificate.
+func validateRequestKeyAlgorithm(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	if ko.Spec.KeyAlgorithm == nil {
+		return errs
+	}
+	algorithm := normalizeKeyAlgorithm(*ko.Spec.KeyAlgorithm)
+	for _, supported := range requestKeyAlgorithms {
+		if algorithm == supported {
+			return errs
+		}
+	}
+	errs = append(errs, field.NotSupported(
+		specPath.Child("keyAlgorithm"),
+		*ko.Spec.KeyAlgorithm,
+		requestKeyAlgorithms,
+	))
+	return errs
+}
+
+// validateDomainNames checks the DomainName and SubjectAlternativeNames of
+// the supplied Certificate.
+func validateDomainNames(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	if ko.Spec.DomainName != nil {
+		errs = append(errs, validateDomainName(
+			specPath.Child("domainName"),
+			*ko.Spec.DomainName,
+			maxLengthDomainName,
+		)...)
+	}
+	sansPath := specPath.Child("subjectAlternativeNames")
+	for i, san := range ko.Spec.SubjectAlternativeNames {
+		if san == nil {
+			errs = append(errs, field.Required(sansPath.Index(i), ""))
+			continue
+		}
+		errs = append(errs, validateDomainName(
+			sansPath.Index(i),
+			*san,
+			maxLengthSubjectAlternativeName,
+		)...)
+	}
+	if n := len(subjectAlternativeNameSet(&resource{ko})); n > limitSubjectAlternativeNames {
+		errs = append(errs, field.TooMany(sansPath, n, limitSubjectAlternativeNames))
+	}
+	return errs
+}
+
+// validateDomainName checks that the supplied name is a fully qualified
+// domain name ACM can issue a certificate for, optionally starting with a
+// wildcard label.
+func validateDomainName(path *field.Path, name string, maxLength int) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(name) > maxLength {
+		errs = append(errs, field.TooLong(path, name, maxLength))
+		return errs
+	}
+	if strings.Contains(name, "*") {
+		if errs = validateWildcard(path, name); len(errs) > 0 {
+			return errs
+		}
+	}
+	labels := strings.Split(strings.TrimPrefix(name, "*."), ".")
+	if len(labels) < 2 {
+		errs = append(errs, field.Invalid(path, name, "must be a fully qualified domain name, as in example.com"))
+		return errs
+	}
+	for _, label := range labels {
+		if !domainLabelRegexp.MatchString(label) {
+			errs = append(errs, field.Invalid(path, name, fmt.Sprintf(
+				"label %q must consist of 1 to 63 letters, digits or '-', "+
+					"and must start and end with a letter or digit",
+				label,
+			)))
+			break
+		}
+	}
+	return errs
+}
+
+// validateWildcard checks that a wildcard in the supplied domain name is
+// used the way ACM supports it: as the complete leftmost label, followed by
+// at least two more labels.
+func validateWildcard(path *field.Path, name string) field.ErrorList {
+	errs := field.ErrorList{}
+	rest, found := strings.CutPrefix(name, "*.")
+	switch {
+	case !found:
+		errs = append(errs, field.Invalid(path, name, "a wildcard must be the complete leftmost label, as in *.example.com"))
+	case strings.Contains(rest, "*"):
+		errs = append(errs, field.Invalid(path, name, "only one wildcard is allowed"))
+	case !strings.Contains(rest, "."):
+		errs = append(errs, field.Invalid(path, name, fmt.Sprintf("a wildcard cannot cover a top-level domain such as %q", rest)))
+	}
+	return errs
+}
+
+// validateDomainValidationOptions checks that every DomainValidationOption
+// of the supplied Certificate refers to one of its domain names and, for
+// public certificates, that there are no more options than DNS validation
+// can handle.
+func validateDomainValidationOptions(ko *svcapitypes.Certificate) field.ErrorList {
+	errs := field.ErrorList{}
+	path := specPath.Child("domainValidationOptions")
+	// Because we require DNS validation for public certificates (email
+	// validation not being automatable), we need to limit the number of
+	// chained CNAME records in the DomainValidationOptions field, since DNS
+	// validation only works on up to 5 subdomains.
+	if n := len(ko.Spec.DomainValidationOptions); isPublicRequestSpec(ko) && n > limitDomainValidationOptionsPublic {
+		errs = append(errs, field.TooMany(path, n, limitDomainValidationOptionsPublic))
+	}
+	names := subjectAlternativeNameSet(&resource{ko})
+	for i, dvo := range ko.Spec.DomainValidationOptions {
+		namePath := path.Index(i).Child("domainName")
+		if dvo == nil || dvo.DomainName == nil {
+			errs = append(errs, field.Required(namePath, ""))
+			continue
+		}
+		if _, ok := names[strings.ToLower(*dvo.DomainName)]; !ok {
+			errs = append(errs, field.Invalid(
+				namePath,
+				*dvo.DomainName,
+				"must be the domainName or one of the subjectAlternativeNames",
+			))
+		}
+	}
+	return errs
+}
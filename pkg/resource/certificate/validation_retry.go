@@ -0,0 +1,201 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// validationTimeout is how long ACM tries to validate a requested
+	// certificate before setting its status to VALIDATION_TIMED_OUT.
+	validationTimeout = 72 * time.Hour
+
+	// eventReasonValidationRetried is the reason of the Event recorded when
+	// a certificate that failed validation is requested again.
+	eventReasonValidationRetried = "ValidationRetried"
+)
+
+// setValidationExpiresAt sets Status.ValidationExpiresAt of the supplied
+// object to the time ACM stops trying to validate the certificate while it
+// is pending validation, and clears it otherwise.
+func setValidationExpiresAt(ko *svcapitypes.Certificate) {
+	ko.Status.ValidationExpiresAt = nil
+	if ko.Status.Status == nil ||
+		*ko.Status.Status != string(svcapitypes.CertificateStatus_SDK_PENDING_VALIDATION) ||
+		ko.Status.CreatedAt == nil {
+		return
+	}
+	ko.Status.ValidationExpiresAt = &metav1.Time{Time: ko.Status.CreatedAt.Add(validationTimeout)}
+}
+
+// validationRetryable returns true if the certificate observed in the
+// supplied object failed validation in a way that the supplied retry policy
+// requests the certificate again for: it timed out, or failed for one of
+// the listed reasons.
+func validationRetryable(
+	policy *svcapitypes.ValidationRetryPolicy,
+	ko *svcapitypes.Certificate,
+) bool {
+	if policy == nil || ko.Status.Status == nil {
+		return false
+	}
+	switch svcapitypes.CertificateStatus_SDK(*ko.Status.Status) {
+	case svcapitypes.CertificateStatus_SDK_VALIDATION_TIMED_OUT:
+		return true
+	case svcapitypes.CertificateStatus_SDK_FAILED:
+		return ko.Status.FailureReason != nil &&
+			slices.Contains(policy.FailureReasons, *ko.Status.FailureReason)
+	}
+	return false
+}
+
+// compareValidationStatus forces an update when the certificate failed
+// validation and the resource opted into requesting it again, so that
+// sdkUpdate gets a chance to do so.
+func compareValidationStatus(
+	delta *ackcompare.Delta,
+	a *resource,
+	b *resource,
+) {
+	// NOTE: ack runtime ONLY goes into update if delta key starts with "Spec"
+	// https://github.com/aws-controllers-k8s/runtime/blob/main/pkg/runtime/reconciler.go#L894-L903
+	if !isImportSpec(a.ko) && validationRetryable(a.ko.Spec.ValidationRetryPolicy, b.ko) {
+		delta.Add("Spec.Status.ValidationRetries", b.ko.Status.Status, nil)
+	}
+}
+
+// retryValidation requests the certificate again after it failed
+// validation, as allowed by Spec.ValidationRetryPolicy. The resource is
+// switched over to the new certificate straight away, since the failed one
+// can never be issued, and the failed certificate is recorded in
+// Status.RetiredCertificateARNs to be deleted. Once MaxAttempts requests
+// have been made, a Terminal condition is set instead.
+func (rm *resourceManager) retryValidation(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+) (updated *resource, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.retryValidation")
+	defer func() { exit(err) }()
+
+	failure := *latest.ko.Status.Status
+	if latest.ko.Status.FailureReason != nil {
+		failure += ": " + *latest.ko.Status.FailureReason
+	}
+	var retries int64
+	if latest.ko.Status.ValidationRetries != nil {
+		retries = *latest.ko.Status.ValidationRetries
+	}
+	if retries >= desired.ko.Spec.ValidationRetryPolicy.MaxAttempts {
+		return nil, ackerr.NewTerminalError(fmt.Errorf(
+			"certificate failed validation (%s) and was already requested again the maximum of %d times",
+			failure, retries,
+		))
+	}
+
+	ko := desired.ko.DeepCopy()
+	rm.setStatusDefaults(ko)
+	arn, err := rm.requestValidationRetryCertificate(ctx, desired, ko, retries+1)
+	if err != nil {
+		return &resource{ko}, err
+	}
+	if ko.Status.ACKResourceMetadata.ARN != nil {
+		failedARN := string(*ko.Status.ACKResourceMetadata.ARN)
+		ko.Status.RetiredCertificateARNs = append(ko.Status.RetiredCertificateARNs, aws.String(failedARN))
+		rm.recordEvent(
+			ko, corev1.EventTypeNormal, eventReasonValidationRetried,
+			"Requested certificate %s to replace %s, which failed validation (%s)",
+			arn, failedARN, failure,
+		)
+	}
+	resourceARN := ackv1alpha1.AWSResourceName(arn)
+	ko.Status.ACKResourceMetadata.ARN = &resourceARN
+	ko.Status.ValidationRetries = aws.Int64(retries + 1)
+	ko.Status.Status = nil
+	ko.Status.FailureReason = nil
+	ko.Status.DomainValidations = nil
+	ko.Status.CreatedAt = nil
+	ko.Status.ValidationExpiresAt = nil
+	rlog.Info("requested certificate again after it failed validation", "arn", arn, "failure", failure)
+	return &resource{ko}, ackrequeue.NeededAfter(
+		fmt.Errorf("certificate failed validation (%s) and was requested again", failure),
+		requeuePending,
+	)
+}
+
+// requestValidationRetryCertificate calls RequestCertificate with the
+// desired parameters once approved, recording the approval in the status
+// of ko, and returns the ARN of the new certificate. The idempotency token
+// is derived from the object's UID and the attempt number so that a
+// request retried before the status could be saved does not create a
+// second certificate.
+func (rm *resourceManager) requestValidationRetryCertificate(
+	ctx context.Context,
+	desired *resource,
+	ko *svcapitypes.Certificate,
+	attempt int64,
+) (string, error) {
+	if err := validateCertificateRequest(desired); err != nil {
+		return "", err
+	}
+	req := requestedPolicyRequest(desired.ko)
+	if err := rm.checkCertificatePolicies(ctx, desired.ko.Namespace, req); err != nil {
+		return "", err
+	}
+	if err := rm.awaitRequestApproval(ctx, ko, req); err != nil {
+		return "", err
+	}
+	input, err := rm.newCreateRequestPayload(ctx, desired)
+	if err != nil {
+		return "", err
+	}
+	if err := rm.setRequestCertificateDefaults(ctx, desired, input); err != nil {
+		return "", err
+	}
+	input.IdempotencyToken = aws.String(validationRetryIdempotencyToken(desired.ko, attempt))
+
+	resp, err := rm.sdkapi.RequestCertificate(ctx, input)
+	rm.metrics.RecordAPICall("CREATE", "RequestCertificate", err)
+	if err != nil {
+		return "", err
+	}
+	return *resp.CertificateArn, nil
+}
+
+// validationRetryIdempotencyToken returns a RequestCertificate idempotency
+// token that is stable for a given attempt to request the certificate of
+// the supplied object again.
+func validationRetryIdempotencyToken(ko *svcapitypes.Certificate, attempt int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/validation-retry/%d", ko.UID, attempt)))
+	// IdempotencyToken is limited to 32 characters
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,486 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// errorFields returns the field paths of the supplied errors.
+func errorFields(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestValidateReplacedFields(t *testing.T) {
+	arn := ackv1alpha1.AWSResourceName("arn:aws:acm:us-west-2:111122223333:certificate/old")
+	requested := func(spec svcapitypes.CertificateSpec) *svcapitypes.Certificate {
+		ko := newTestCertificate(spec).ko
+		ko.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{ARN: &arn}
+		return ko
+	}
+	oldSpec := svcapitypes.CertificateSpec{
+		DomainName:              aws.String("www.example.com"),
+		SubjectAlternativeNames: aws.StringSlice([]string{"www.example.com", "api.example.com"}),
+		KeyAlgorithm:            aws.String("RSA_2048"),
+	}
+	tests := []struct {
+		name string
+		// old is the spec of the Certificate before the update, oldSpec if
+		// nil.
+		old        *svcapitypes.CertificateSpec
+		notCreated bool
+		update     func(spec *svcapitypes.CertificateSpec)
+		want       []string
+	}{
+		{
+			name:   "no change",
+			update: func(spec *svcapitypes.CertificateSpec) {},
+			want:   []string{},
+		},
+		{
+			name:   "domain name",
+			update: func(spec *svcapitypes.CertificateSpec) { spec.DomainName = aws.String("web.example.com") },
+			want:   []string{"spec.domainName"},
+		},
+		{
+			name: "domain name with Replace",
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.DomainName = aws.String("web.example.com")
+				spec.ReplacementPolicy = ptr(svcapitypes.ReplacementPolicyReplace)
+			},
+			want: []string{},
+		},
+		{
+			name: "domain name before the certificate is requested",
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.DomainName = aws.String("web.example.com")
+			},
+			notCreated: true,
+			want:       []string{},
+		},
+		{
+			name: "subject alternative name added",
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.SubjectAlternativeNames = append(spec.SubjectAlternativeNames, aws.String("cdn.example.com"))
+			},
+			want: []string{"spec.subjectAlternativeNames"},
+		},
+		{
+			name: "subject alternative names reordered",
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.SubjectAlternativeNames = aws.StringSlice([]string{"API.example.com", "www.example.com"})
+			},
+			want: []string{},
+		},
+		{
+			name: "subject alternative names late initialized",
+			old:  &svcapitypes.CertificateSpec{DomainName: aws.String("www.example.com")},
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.SubjectAlternativeNames = aws.StringSlice([]string{"www.example.com"})
+			},
+			want: []string{},
+		},
+		{
+			name:   "key algorithm",
+			update: func(spec *svcapitypes.CertificateSpec) { spec.KeyAlgorithm = aws.String("EC_prime256v1") },
+			want:   []string{"spec.keyAlgorithm"},
+		},
+		{
+			name:   "key algorithm spelled differently",
+			update: func(spec *svcapitypes.CertificateSpec) { spec.KeyAlgorithm = aws.String("RSA-2048") },
+			want:   []string{},
+		},
+		{
+			name:   "key algorithm removed",
+			update: func(spec *svcapitypes.CertificateSpec) { spec.KeyAlgorithm = nil },
+			want:   []string{"spec.keyAlgorithm"},
+		},
+		{
+			name:   "key algorithm late initialized",
+			old:    &svcapitypes.CertificateSpec{DomainName: aws.String("www.example.com")},
+			update: func(spec *svcapitypes.CertificateSpec) { spec.KeyAlgorithm = aws.String("RSA_2048") },
+			want:   []string{},
+		},
+		{
+			name: "certificate authority",
+			old: &svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.com"),
+				CertificateAuthorityARN: aws.String(testCAARN),
+			},
+			update: func(spec *svcapitypes.CertificateSpec) {
+				spec.CertificateAuthorityARN = aws.String(testCAARN + "-other")
+			},
+			want: []string{"spec.certificateAuthorityARN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := oldSpec
+			if tt.old != nil {
+				spec = *tt.old
+			}
+			old := requested(spec)
+			if tt.notCreated {
+				old.Status.ACKResourceMetadata = nil
+			}
+			ko := old.DeepCopy()
+			tt.update(&ko.Spec)
+			if got := errorFields(validateCertificate(old, ko)); !slices.Equal(got, tt.want) {
+				t.Errorf("validateCertificate errors on %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	request := svcapitypes.CertificateSpec{DomainName: aws.String("www.example.com")}
+	private := svcapitypes.CertificateSpec{
+		DomainName:              aws.String("www.example.com"),
+		CertificateAuthorityARN: aws.String(testCAARN),
+	}
+	imported := svcapitypes.CertificateSpec{
+		Certificate: secretKeyRef("import", "tls.crt"),
+		PrivateKey:  secretKeyRef("import", "tls.key"),
+	}
+	with := func(spec svcapitypes.CertificateSpec, update func(spec *svcapitypes.CertificateSpec)) *svcapitypes.CertificateSpec {
+		update(&spec)
+		return &spec
+	}
+	export := func(value string) *svcapitypes.CertificateOptions {
+		return &svcapitypes.CertificateOptions{Export: aws.String(value)}
+	}
+	tests := []struct {
+		name string
+		// old is the spec of the Certificate before the update, or nil for
+		// a create.
+		old  *svcapitypes.CertificateSpec
+		spec svcapitypes.CertificateSpec
+		want []string
+	}{
+		{name: "request", spec: request, want: []string{}},
+		{name: "import", spec: imported, want: []string{}},
+		{
+			name: "import from a Secret",
+			spec: svcapitypes.CertificateSpec{ImportFrom: &svcapitypes.TLSSecretReference{Name: "tls"}},
+			want: []string{},
+		},
+		{name: "neither import nor request", spec: svcapitypes.CertificateSpec{}, want: []string{"spec.domainName"}},
+		{
+			name: "import with request fields",
+			spec: *with(imported, func(spec *svcapitypes.CertificateSpec) {
+				spec.DomainName = aws.String("www.example.com")
+				spec.KeyAlgorithm = aws.String("RSA_2048")
+				spec.CertificateAuthorityARN = aws.String(testCAARN)
+			}),
+			want: []string{"spec.domainName", "spec.keyAlgorithm", "spec.certificateAuthorityARN"},
+		},
+		{
+			name: "import without private key",
+			spec: svcapitypes.CertificateSpec{Certificate: secretKeyRef("import", "tls.crt")},
+			want: []string{"spec.privateKey"},
+		},
+		{
+			name: "import from a Secret and a cert-manager Certificate",
+			spec: svcapitypes.CertificateSpec{
+				ImportFrom:                &svcapitypes.TLSSecretReference{Name: "tls"},
+				CertManagerCertificateRef: &svcapitypes.CertManagerCertificateReference{Name: "web"},
+				PrivateKey:                secretKeyRef("import", "tls.key"),
+			},
+			want: []string{"spec.certManagerCertificateRef", "spec.privateKey"},
+		},
+		{
+			name: "request with import fields",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.PrivateKey = secretKeyRef("import", "tls.key")
+				spec.CertificateARN = aws.String("arn:aws:acm:us-west-2:111122223333:certificate/imported")
+			}),
+			want: []string{"spec.privateKey", "spec.certificateARN"},
+		},
+		{
+			name: "export imported certificate",
+			spec: *with(imported, func(spec *svcapitypes.CertificateSpec) { spec.ExportTo = secretKeyRef("export", "tls.crt") }),
+			want: []string{"spec.exportTo"},
+		},
+		{
+			name: "export public certificate",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) { spec.ExportTo = secretKeyRef("export", "tls.crt") }),
+			want: []string{},
+		},
+		{
+			name: "export public certificate that is not exportable",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.ExportTo = secretKeyRef("export", "tls.crt")
+				spec.Options = export("DISABLED")
+			}),
+			want: []string{"spec.exportTo"},
+		},
+		{
+			name: "export added to existing public certificate",
+			old:  &request,
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) { spec.ExportTo = secretKeyRef("export", "tls.crt") }),
+			want: []string{"spec.exportTo"},
+		},
+		{
+			name: "export added to existing exportable public certificate",
+			old:  with(request, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("ENABLED") }),
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.ExportTo = secretKeyRef("export", "tls.crt")
+				spec.Options = export("ENABLED")
+			}),
+			want: []string{},
+		},
+		{
+			name: "export preference of private certificate",
+			spec: *with(private, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("ENABLED") }),
+			want: []string{"spec.options.export"},
+		},
+		{
+			name: "export preference of private certificate late initialized",
+			old:  &private,
+			spec: *with(private, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("DISABLED") }),
+			want: []string{},
+		},
+		{
+			name: "export preference of private certificate changed",
+			old:  with(private, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("DISABLED") }),
+			spec: *with(private, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("ENABLED") }),
+			want: []string{"spec.options.export"},
+		},
+		{
+			name: "unknown export preference",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) { spec.Options = export("SOMETIMES") }),
+			want: []string{"spec.options.export"},
+		},
+		{
+			name: "replica regions",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.ReplicaRegions = aws.StringSlice([]string{"us-east-1", "eu-west-1"})
+			}),
+			want: []string{},
+		},
+		{
+			name: "invalid and duplicate replica regions",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.ReplicaRegions = aws.StringSlice([]string{"us-east-1", "Virginia", "us-east-1"})
+			}),
+			want: []string{"spec.replicaRegions[1]", "spec.replicaRegions[2]"},
+		},
+		{
+			name: "replica regions of private certificate",
+			spec: *with(private, func(spec *svcapitypes.CertificateSpec) {
+				spec.ReplicaRegions = aws.StringSlice([]string{"us-east-1"})
+			}),
+			want: []string{"spec.replicaRegions"},
+		},
+		{
+			name: "consumers",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.Consumers = []*svcapitypes.CertificateConsumer{
+					{Kind: ConsumerKindIngress, Name: "web"},
+					{Kind: "Deployment", Name: "web"},
+					{Kind: ConsumerKindIngress, Name: "web"},
+				}
+			}),
+			want: []string{"spec.consumers[1].kind", "spec.consumers[2]"},
+		},
+		{
+			name: "validation retry policy of imported certificate",
+			spec: *with(imported, func(spec *svcapitypes.CertificateSpec) {
+				spec.ValidationRetryPolicy = &svcapitypes.ValidationRetryPolicy{MaxAttempts: 1}
+			}),
+			want: []string{"spec.validationRetryPolicy"},
+		},
+		{
+			name: "invalid validation retry policy",
+			spec: *with(request, func(spec *svcapitypes.CertificateSpec) {
+				spec.ValidationRetryPolicy = &svcapitypes.ValidationRetryPolicy{FailureReasons: []string{"BAD_LUCK"}}
+			}),
+			want: []string{"spec.validationRetryPolicy.maxAttempts", "spec.validationRetryPolicy.failureReasons[0]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old *svcapitypes.Certificate
+			if tt.old != nil {
+				old = newTestCertificate(*tt.old).ko
+			}
+			ko := newTestCertificate(tt.spec).ko
+			if got := errorFields(validateCertificate(old, ko)); !slices.Equal(got, tt.want) {
+				t.Errorf("validateCertificate errors on %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	sans := func(n int) []*string {
+		names := make([]*string, n)
+		for i := range names {
+			names[i] = aws.String(fmt.Sprintf("host%d.example.com", i))
+		}
+		return names
+	}
+	dvos := func(names ...string) []*svcapitypes.DomainValidationOption {
+		options := []*svcapitypes.DomainValidationOption{}
+		for _, name := range names {
+			options = append(options, &svcapitypes.DomainValidationOption{
+				DomainName:       aws.String(name),
+				ValidationDomain: aws.String("example.com"),
+			})
+		}
+		return options
+	}
+	tests := []struct {
+		name string
+		spec svcapitypes.CertificateSpec
+		want []string
+	}{
+		{
+			name: "domain name",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("www.example.com")},
+			want: []string{},
+		},
+		{
+			name: "wildcard",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("*.example.com")},
+			want: []string{},
+		},
+		{
+			name: "single label",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("localhost")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "domain name too long",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String(strings.Repeat("a", 60) + ".example.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "invalid label",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("-www.example.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "wildcard not the leftmost label",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("www.*.example.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "partial wildcard label",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("w*.example.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "two wildcards",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("*.*.example.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "wildcard for a top-level domain",
+			spec: svcapitypes.CertificateSpec{DomainName: aws.String("*.com")},
+			want: []string{"spec.domainName"},
+		},
+		{
+			name: "invalid subject alternative name",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.com"),
+				SubjectAlternativeNames: []*string{aws.String("api.example.com"), aws.String("api_example.com"), nil},
+			},
+			want: []string{"spec.subjectAlternativeNames[1]", "spec.subjectAlternativeNames[2]"},
+		},
+		{
+			name: "too many subject alternative names",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.com"),
+				SubjectAlternativeNames: sans(limitSubjectAlternativeNames),
+			},
+			want: []string{"spec.subjectAlternativeNames"},
+		},
+		{
+			name: "key algorithm",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:   aws.String("www.example.com"),
+				KeyAlgorithm: aws.String("EC_secp384r1"),
+			},
+			want: []string{},
+		},
+		{
+			name: "key algorithm only for imported certificates",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:   aws.String("www.example.com"),
+				KeyAlgorithm: aws.String("RSA_4096"),
+			},
+			want: []string{"spec.keyAlgorithm"},
+		},
+		{
+			name: "domain validation options",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.com"),
+				SubjectAlternativeNames: aws.StringSlice([]string{"api.example.com"}),
+				DomainValidationOptions: dvos("www.example.com", "API.example.com"),
+			},
+			want: []string{},
+		},
+		{
+			name: "domain validation option for another domain",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("www.example.com"),
+				DomainValidationOptions: dvos("www.example.com", "api.example.com"),
+			},
+			want: []string{"spec.domainValidationOptions[1].domainName"},
+		},
+		{
+			name: "too many domain validation options for a public certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("host0.example.com"),
+				SubjectAlternativeNames: sans(6),
+				DomainValidationOptions: dvos("host0.example.com", "host1.example.com", "host2.example.com",
+					"host3.example.com", "host4.example.com", "host5.example.com"),
+			},
+			want: []string{"spec.domainValidationOptions"},
+		},
+		{
+			name: "many domain validation options for a private certificate",
+			spec: svcapitypes.CertificateSpec{
+				DomainName:              aws.String("host0.example.com"),
+				SubjectAlternativeNames: sans(6),
+				CertificateAuthorityARN: aws.String(testCAARN),
+				DomainValidationOptions: dvos("host0.example.com", "host1.example.com", "host2.example.com",
+					"host3.example.com", "host4.example.com", "host5.example.com"),
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := newTestCertificate(tt.spec).ko
+			if got := errorFields(validateCertificate(nil, ko)); !slices.Equal(got, tt.want) {
+				t.Errorf("validateCertificate errors on %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
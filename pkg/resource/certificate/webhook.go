@@ -0,0 +1,195 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"context"
+	"reflect"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	svcapitypes "github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func init() {
+	if err := ackrtwebhook.RegisterWebhook(ackrtwebhook.New(
+		svcapitypes.GroupVersion.Version,
+		GroupKind.Kind,
+		"validating",
+		func(mgr ctrlrt.Manager) error {
+			return ctrlrt.NewWebhookManagedBy(mgr, &svcapitypes.Certificate{}).
+				WithValidator(&certificateValidator{kc: mgr.GetAPIReader()}).
+				Complete()
+		},
+	)); err != nil {
+		panic(err)
+	}
+	if err := ackrtwebhook.RegisterWebhook(ackrtwebhook.New(
+		svcapitypes.GroupVersion.Version,
+		"CertificateApproval",
+		"mutating",
+		func(mgr ctrlrt.Manager) error {
+			return ctrlrt.NewWebhookManagedBy(mgr, &svcapitypes.CertificateApproval{}).
+				WithDefaulter(&certificateApprovalDefaulter{}).
+				Complete()
+		},
+	)); err != nil {
+		panic(err)
+	}
+}
+
+// +kubebuilder:webhook:path=/validate-acm-services-k8s-aws-v1alpha1-certificate,mutating=false,failurePolicy=fail,sideEffects=None,groups=acm.services.k8s.aws,resources=certificates,verbs=create;update,versions=v1alpha1,name=vcertificate.acm.services.k8s.aws,admissionReviewVersions=v1
+
+// certificateValidator rejects Certificate resources whose spec the
+// controller would otherwise only be able to report as a Terminal condition
+// after the resource was accepted.
+type certificateValidator struct {
+	// kc reads the CertificatePolicies and CertificateClasses the spec is
+	// checked against.
+	kc client.Reader
+}
+
+var _ admission.Validator[*svcapitypes.Certificate] = &certificateValidator{}
+
+// ValidateCreate validates the spec of a new Certificate.
+func (v *certificateValidator) ValidateCreate(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (admission.Warnings, error) {
+	if skipValidation(ko) {
+		return nil, nil
+	}
+	errs := validateCertificate(nil, ko)
+	policyErrs, err := validateCertificatePolicies(ctx, v.kc, nil, ko)
+	if err != nil {
+		return nil, err
+	}
+	return nil, invalidError(ko, append(errs, policyErrs...))
+}
+
+// ValidateUpdate validates the changes made to the spec of a Certificate.
+func (v *certificateValidator) ValidateUpdate(
+	ctx context.Context,
+	old *svcapitypes.Certificate,
+	ko *svcapitypes.Certificate,
+) (admission.Warnings, error) {
+	// Objects that are being deleted must always be updatable, otherwise
+	// finalizers could never be removed.
+	if skipValidation(ko) || !ko.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if equality.Semantic.DeepEqual(old.Spec, ko.Spec) {
+		return nil, nil
+	}
+	errs := validateCertificate(old, ko)
+	policyErrs, err := validateCertificatePolicies(ctx, v.kc, old, ko)
+	if err != nil {
+		return nil, err
+	}
+	return nil, invalidError(ko, append(errs, policyErrs...))
+}
+
+// ValidateDelete allows all Certificates to be deleted.
+func (v *certificateValidator) ValidateDelete(
+	ctx context.Context,
+	ko *svcapitypes.Certificate,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// +kubebuilder:webhook:path=/mutate-acm-services-k8s-aws-v1alpha1-certificateapproval,mutating=true,failurePolicy=fail,sideEffects=None,groups=acm.services.k8s.aws,resources=certificateapprovals,verbs=create,versions=v1alpha1,name=mcertificateapproval.acm.services.k8s.aws,admissionReviewVersions=v1
+
+// certificateApprovalDefaulter records the user who creates a
+// CertificateApproval as its approver.
+type certificateApprovalDefaulter struct{}
+
+var _ admission.Defaulter[*svcapitypes.CertificateApproval] = &certificateApprovalDefaulter{}
+
+// Default sets the approver of a new CertificateApproval to the name of the
+// user creating it, replacing any value given.
+func (d *certificateApprovalDefaulter) Default(
+	ctx context.Context,
+	approval *svcapitypes.CertificateApproval,
+) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	approval.Spec.Approver = req.UserInfo.Username
+	return nil
+}
+
+// skipValidation returns true if the spec of the supplied Certificate is
+// filled in from an existing ACM certificate by the controller, in which
+// case it describes whatever ACM holds rather than user intent.
+func skipValidation(ko *svcapitypes.Certificate) bool {
+	_, adopting := ko.GetAnnotations()[ackv1alpha1.AnnotationAdoptionPolicy]
+	return adopting
+}
+
+// validateCertificatePolicies checks a new Certificate, or a change to the
+// domain names, certificate authority or key algorithm of a Certificate,
+// against the CertificatePolicies applying to its namespace, with the
+// defaults of its CertificateClass. Imported certificates are checked by
+// the controller once it has read them.
+func validateCertificatePolicies(
+	ctx context.Context,
+	kc client.Reader,
+	old *svcapitypes.Certificate,
+	ko *svcapitypes.Certificate,
+) (field.ErrorList, error) {
+	if isImportSpec(ko) {
+		return nil, nil
+	}
+	if old != nil && reflect.DeepEqual(requestedPolicyRequest(old), requestedPolicyRequest(ko)) {
+		return nil, nil
+	}
+	merged := ko
+	if ko.Spec.CertificateClassName != nil && *ko.Spec.CertificateClassName != "" {
+		// A class that does not exist yet is reported by the controller.
+		if class, err := readCertificateClass(ctx, kc, *ko.Spec.CertificateClassName); err == nil {
+			merged = ko.DeepCopy()
+			mergeCertificateClass(merged, &class.Spec)
+		}
+	}
+	violation, err := certificatePolicyViolation(ctx, kc, ko.Namespace, requestedPolicyRequest(merged))
+	if err != nil {
+		return nil, err
+	}
+	if violation == "" {
+		return nil, nil
+	}
+	return field.ErrorList{field.Forbidden(field.NewPath("spec"), violation)}, nil
+}
+
+// invalidError returns an Invalid API error for the supplied Certificate,
+// or nil if errs is empty.
+func invalidError(ko *svcapitypes.Certificate, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupKind.Group, Kind: GroupKind.Kind},
+		ko.GetName(),
+		errs,
+	)
+}
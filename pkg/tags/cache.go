@@ -0,0 +1,178 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+)
+
+// cacheHitKey marks the ResultMetadata of ListTagsForCertificate outputs
+// that were served from a Cache rather than by ACM.
+type cacheHitKey struct{}
+
+// Cache holds the tags of certificates, keyed by ARN, as last listed from
+// ACM. It is safe for concurrent use.
+type Cache struct {
+	mu        sync.Mutex
+	entries   map[string]cacheEntry
+	lastSweep time.Time
+}
+
+type cacheEntry struct {
+	tags     []svcsdktypes.Tag
+	listedAt time.Time
+}
+
+// NewCache returns an empty Cache.
+func NewCache() *Cache {
+	return &Cache{entries: map[string]cacheEntry{}}
+}
+
+// get returns the tags of the supplied certificate if they were listed
+// less than refreshInterval ago.
+func (c *Cache) get(
+	arn string,
+	refreshInterval time.Duration,
+	now time.Time,
+) ([]svcsdktypes.Tag, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, found := c.entries[arn]
+	if !found || now.Sub(entry.listedAt) >= refreshInterval {
+		return nil, false
+	}
+	return append([]svcsdktypes.Tag{}, entry.tags...), true
+}
+
+// set stores the tags of the supplied certificate. Entries that have not
+// been refreshed for refreshInterval, for example those of deleted
+// certificates, are dropped at most once per refreshInterval.
+func (c *Cache) set(
+	arn string,
+	tags []svcsdktypes.Tag,
+	refreshInterval time.Duration,
+	now time.Time,
+) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastSweep) >= refreshInterval {
+		for k, entry := range c.entries {
+			if now.Sub(entry.listedAt) >= refreshInterval {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	c.entries[arn] = cacheEntry{
+		tags:     append([]svcsdktypes.Tag{}, tags...),
+		listedAt: now,
+	}
+}
+
+// invalidate drops the tags of the supplied certificate.
+func (c *Cache) invalidate(arn string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, arn)
+}
+
+// CachingClient is a tags client that serves ListTagsForCertificate from a
+// Cache. Tags are listed from ACM again once refreshInterval has passed, to
+// pick up changes made outside of the controller, or after they were
+// changed through the client.
+type CachingClient struct {
+	client          tagsClient
+	cache           *Cache
+	refreshInterval time.Duration
+}
+
+var _ tagsClient = &CachingClient{}
+
+// NewCachingClient returns a CachingClient that calls the supplied client
+// and stores tags in the supplied Cache. A refreshInterval of zero or less
+// disables caching.
+func NewCachingClient(
+	client tagsClient,
+	cache *Cache,
+	refreshInterval time.Duration,
+) *CachingClient {
+	return &CachingClient{
+		client:          client,
+		cache:           cache,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// ListTagsForCertificate returns the cached tags of the certificate, or
+// lists and caches them if they are not cached or are stale.
+func (c *CachingClient) ListTagsForCertificate(
+	ctx context.Context,
+	input *svcsdk.ListTagsForCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.ListTagsForCertificateOutput, error) {
+	if c.refreshInterval <= 0 || input.CertificateArn == nil {
+		return c.client.ListTagsForCertificate(ctx, input, optFns...)
+	}
+	arn := *input.CertificateArn
+	if tags, found := c.cache.get(arn, c.refreshInterval, time.Now()); found {
+		output := &svcsdk.ListTagsForCertificateOutput{Tags: tags}
+		output.ResultMetadata.Set(cacheHitKey{}, true)
+		return output, nil
+	}
+	output, err := c.client.ListTagsForCertificate(ctx, input, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(arn, output.Tags, c.refreshInterval, time.Now())
+	return output, nil
+}
+
+// AddTagsToCertificate adds tags to the certificate and drops its cached
+// tags.
+func (c *CachingClient) AddTagsToCertificate(
+	ctx context.Context,
+	input *svcsdk.AddTagsToCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.AddTagsToCertificateOutput, error) {
+	if input.CertificateArn != nil {
+		// Invalidate even if the call fails, as some tags may have been
+		// added anyway.
+		defer c.cache.invalidate(*input.CertificateArn)
+	}
+	return c.client.AddTagsToCertificate(ctx, input, optFns...)
+}
+
+// RemoveTagsFromCertificate removes tags from the certificate and drops its
+// cached tags.
+func (c *CachingClient) RemoveTagsFromCertificate(
+	ctx context.Context,
+	input *svcsdk.RemoveTagsFromCertificateInput,
+	optFns ...func(*svcsdk.Options),
+) (*svcsdk.RemoveTagsFromCertificateOutput, error) {
+	if input.CertificateArn != nil {
+		defer c.cache.invalidate(*input.CertificateArn)
+	}
+	return c.client.RemoveTagsFromCertificate(ctx, input, optFns...)
+}
+
+// fromCache returns true if the supplied output was served from a Cache.
+func fromCache(output *svcsdk.ListTagsForCertificateOutput) bool {
+	hit, _ := output.ResultMetadata.Get(cacheHitKey{}).(bool)
+	return hit
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 
 	"github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
 
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
@@ -48,6 +49,10 @@ func SyncTags(
 	exit := rlog.Trace("rm.syncTags")
 	defer func() { exit(err) }()
 
+	if err = Validate(aTags); err != nil {
+		return ackerr.NewTerminalError(err)
+	}
+
 	desiredTags := map[string]*string{}
 	for _, t := range aTags {
 		desiredTags[*t.Key] = t.Value
@@ -72,36 +77,63 @@ func SyncTags(
 		}
 	}
 
-	if len(toDelete) > 0 {
-		for k, v := range toDelete {
-			rlog.Debug("removing tag from resource", "key", k, "value", *v)
-		}
-		if err = removeTags(
-			ctx,
-			client,
-			mr,
-			resourceID,
-			toDelete,
-		); err != nil {
-			return err
+	// Tags are added before the ones no longer desired are removed, so that
+	// tags whose key changed are never missing from the certificate. That
+	// is not possible if the certificate would briefly have more tags than
+	// ACM allows, in which case they are removed first.
+	newKeys := 0
+	for k := range toAdd {
+		if _, found := existingTags[k]; !found {
+			newKeys++
 		}
 	}
-	if len(toAdd) > 0 {
-		for k, v := range toAdd {
-			rlog.Debug("adding tag to resource", "key", k, "value", *v)
-		}
-		if err = addTags(
-			ctx,
-			client,
-			mr,
-			resourceID,
-			toAdd,
-		); err != nil {
+	if len(existingTags)+newKeys > MaxTags {
+		if err = syncRemoveTags(ctx, client, mr, resourceID, toDelete); err != nil {
 			return err
 		}
+		return syncAddTags(ctx, client, mr, resourceID, toAdd)
+	}
+	if err = syncAddTags(ctx, client, mr, resourceID, toAdd); err != nil {
+		return err
+	}
+	return syncRemoveTags(ctx, client, mr, resourceID, toDelete)
+}
+
+// syncAddTags adds the supplied Tags to the supplied resource, if any.
+func syncAddTags(
+	ctx context.Context,
+	client tagsClient,
+	mr metricsRecorder,
+	resourceID string,
+	toAdd map[string]*string,
+) error {
+	if len(toAdd) == 0 {
+		return nil
+	}
+	rlog := ackrtlog.FromContext(ctx)
+	for k, v := range toAdd {
+		rlog.Debug("adding tag to resource", "key", k, "value", *v)
 	}
+	return addTags(ctx, client, mr, resourceID, toAdd)
+}
 
-	return nil
+// syncRemoveTags removes the supplied Tags from the supplied resource, if
+// any.
+func syncRemoveTags(
+	ctx context.Context,
+	client tagsClient,
+	mr metricsRecorder,
+	resourceID string,
+	toDelete map[string]*string,
+) error {
+	if len(toDelete) == 0 {
+		return nil
+	}
+	rlog := ackrtlog.FromContext(ctx)
+	for k, v := range toDelete {
+		rlog.Debug("removing tag from resource", "key", k, "value", *v)
+	}
+	return removeTags(ctx, client, mr, resourceID, toDelete)
 }
 
 // addTags adds the supplied Tags to the supplied resource
@@ -184,10 +216,13 @@ func ListTags(
 			CertificateArn: &resourceARN,
 		},
 	)
-	mr.RecordAPICall("GET", "ListTagsForCertificate", err)
 	if err != nil {
+		mr.RecordAPICall("GET", "ListTagsForCertificate", err)
 		return nil, err
 	}
+	if !fromCache(listTagsOfResourceOutput) {
+		mr.RecordAPICall("GET", "ListTagsForCertificate", err)
+	}
 	return resourceTagsFromSDKTags(listTagsOfResourceOutput.Tags), nil
 }
 
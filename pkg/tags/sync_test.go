@@ -0,0 +1,110 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/acm"
+
+	"github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+// recordingClient is a tagsClient that records the calls made to it.
+type recordingClient struct {
+	calls []string
+}
+
+func (c *recordingClient) AddTagsToCertificate(_ context.Context, input *svcsdk.AddTagsToCertificateInput, _ ...func(*svcsdk.Options)) (*svcsdk.AddTagsToCertificateOutput, error) {
+	for _, t := range input.Tags {
+		c.calls = append(c.calls, "add "+aws.ToString(t.Key))
+	}
+	return &svcsdk.AddTagsToCertificateOutput{}, nil
+}
+
+func (c *recordingClient) ListTagsForCertificate(context.Context, *svcsdk.ListTagsForCertificateInput, ...func(*svcsdk.Options)) (*svcsdk.ListTagsForCertificateOutput, error) {
+	return &svcsdk.ListTagsForCertificateOutput{}, nil
+}
+
+func (c *recordingClient) RemoveTagsFromCertificate(_ context.Context, input *svcsdk.RemoveTagsFromCertificateInput, _ ...func(*svcsdk.Options)) (*svcsdk.RemoveTagsFromCertificateOutput, error) {
+	for _, t := range input.Tags {
+		c.calls = append(c.calls, "remove "+aws.ToString(t.Key))
+	}
+	return &svcsdk.RemoveTagsFromCertificateOutput{}, nil
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) RecordAPICall(string, string, error) {}
+
+func TestSyncTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  []*v1alpha1.Tag
+		existing []*v1alpha1.Tag
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "in sync",
+			desired:  []*v1alpha1.Tag{tag("team", "web")},
+			existing: []*v1alpha1.Tag{tag("team", "web")},
+		},
+		{
+			name:     "value changed",
+			desired:  []*v1alpha1.Tag{tag("team", "api")},
+			existing: []*v1alpha1.Tag{tag("team", "web")},
+			want:     []string{"add team"},
+		},
+		{
+			name:     "key renamed",
+			desired:  []*v1alpha1.Tag{tag("owner", "web")},
+			existing: []*v1alpha1.Tag{tag("team", "web")},
+			want:     []string{"add owner", "remove team"},
+		},
+		{
+			name:     "key renamed at MaxTags",
+			desired:  append(manyTags(MaxTags-1), tag("owner", "web")),
+			existing: append(manyTags(MaxTags-1), tag("team", "web")),
+			want:     []string{"remove team", "add owner"},
+		},
+		{
+			name:     "value changed at MaxTags",
+			desired:  append(manyTags(MaxTags-1), tag("team", "api")),
+			existing: append(manyTags(MaxTags-1), tag("team", "web")),
+			want:     []string{"add team"},
+		},
+		{
+			name:     "invalid tags",
+			desired:  []*v1alpha1.Tag{tag("aws:team", "web")},
+			existing: []*v1alpha1.Tag{tag("team", "web")},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingClient{}
+			err := SyncTags(context.Background(), client, nopMetrics{}, "arn", tt.desired, tt.existing)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SyncTags error = %v, want error: %v", err, tt.wantErr)
+			}
+			if !slices.Equal(client.calls, tt.want) {
+				t.Errorf("SyncTags calls = %q, want %q", client.calls, tt.want)
+			}
+		})
+	}
+}
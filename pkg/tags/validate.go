@@ -0,0 +1,117 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+const (
+	// MaxTags is the maximum number of tags ACM allows on a certificate.
+	MaxTags = 50
+	// maxKeyLength and maxValueLength are the maximum lengths, in
+	// characters, of tag keys and values.
+	maxKeyLength   = 128
+	maxValueLength = 256
+	// reservedPrefix is the prefix of tag keys reserved for use by AWS.
+	reservedPrefix = "aws:"
+)
+
+// tagCharacters matches the characters ACM allows in tag keys and values.
+var tagCharacters = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// TagError describes a tag that ACM would reject.
+type TagError struct {
+	// Key is the key of the offending tag.
+	Key string
+	// Reason describes what is wrong with the tag.
+	Reason string
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("tag %q: %s", e.Key, e.Reason)
+}
+
+// Validate checks the supplied tags against the limits and rules ACM
+// applies to the tags of a certificate, so that they can be reported before
+// any API call is made. The returned error joins a *TagError for every
+// offending tag, and an error for the number of tags if it is over MaxTags.
+func Validate(tags []*v1alpha1.Tag) error {
+	var errs []error
+	if len(tags) > MaxTags {
+		errs = append(errs, fmt.Errorf(
+			"too many tags: %d, a certificate can have at most %d tags",
+			len(tags), MaxTags,
+		))
+	}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		if t == nil || t.Key == nil {
+			errs = append(errs, &TagError{Reason: "key is required"})
+			continue
+		}
+		key := *t.Key
+		if seen[key] {
+			errs = append(errs, &TagError{Key: key, Reason: "duplicate key"})
+			continue
+		}
+		seen[key] = true
+		if reason := validateKey(key); reason != "" {
+			errs = append(errs, &TagError{Key: key, Reason: reason})
+			continue
+		}
+		if t.Value != nil {
+			if reason := validateValue(*t.Value); reason != "" {
+				errs = append(errs, &TagError{Key: key, Reason: reason})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateKey returns why the supplied tag key is invalid, or an empty
+// string if it is valid.
+func validateKey(key string) string {
+	switch n := utf8.RuneCountInString(key); {
+	case n == 0:
+		return "key must not be empty"
+	case n > maxKeyLength:
+		return fmt.Sprintf("key is %d characters long, at most %d are allowed", n, maxKeyLength)
+	}
+	if !tagCharacters.MatchString(key) {
+		return "key may only contain letters, numbers, spaces and _.:/=+-@"
+	}
+	if strings.HasPrefix(strings.ToLower(key), reservedPrefix) {
+		return fmt.Sprintf("keys starting with %q are reserved for use by AWS", reservedPrefix)
+	}
+	return ""
+}
+
+// validateValue returns why the supplied tag value is invalid, or an empty
+// string if it is valid.
+func validateValue(value string) string {
+	if n := utf8.RuneCountInString(value); n > maxValueLength {
+		return fmt.Sprintf("value is %d characters long, at most %d are allowed", n, maxValueLength)
+	}
+	if !tagCharacters.MatchString(value) {
+		return "value may only contain letters, numbers, spaces and _.:/=+-@"
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/aws-controllers-k8s/acm-controller/apis/v1alpha1"
+)
+
+func tag(key string, value string) *v1alpha1.Tag {
+	return &v1alpha1.Tag{Key: aws.String(key), Value: aws.String(value)}
+}
+
+func manyTags(n int) []*v1alpha1.Tag {
+	tags := make([]*v1alpha1.Tag, n)
+	for i := range tags {
+		tags[i] = tag(fmt.Sprintf("key-%d", i), "value")
+	}
+	return tags
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []*v1alpha1.Tag
+		// wantKeys are the keys of the expected TagErrors.
+		wantKeys     []string
+		wantTooMany  bool
+		wantContains string
+	}{
+		{name: "no tags"},
+		{name: "valid tags", tags: []*v1alpha1.Tag{tag("team", "web"), tag("cost center", "a/b:c=d+e-f@g_h.i"), tag("empty", "")}},
+		{name: "unicode", tags: []*v1alpha1.Tag{tag("équipe", "données")}},
+		{name: "MaxTags tags", tags: manyTags(MaxTags)},
+		{name: "too many tags", tags: manyTags(MaxTags + 1), wantTooMany: true},
+		{name: "missing key", tags: []*v1alpha1.Tag{{Value: aws.String("web")}}, wantKeys: []string{""}, wantContains: "key is required"},
+		{name: "empty key", tags: []*v1alpha1.Tag{tag("", "web")}, wantKeys: []string{""}, wantContains: "must not be empty"},
+		{name: "duplicate key", tags: []*v1alpha1.Tag{tag("team", "web"), tag("team", "api")}, wantKeys: []string{"team"}, wantContains: "duplicate key"},
+		{name: "key too long", tags: []*v1alpha1.Tag{tag(strings.Repeat("k", maxKeyLength+1), "")}, wantKeys: []string{strings.Repeat("k", maxKeyLength+1)}},
+		{name: "value too long", tags: []*v1alpha1.Tag{tag("team", strings.Repeat("v", maxValueLength+1))}, wantKeys: []string{"team"}},
+		{name: "invalid key character", tags: []*v1alpha1.Tag{tag("team!", "web")}, wantKeys: []string{"team!"}},
+		{name: "invalid value character", tags: []*v1alpha1.Tag{tag("team", "web#1")}, wantKeys: []string{"team"}},
+		{name: "reserved prefix", tags: []*v1alpha1.Tag{tag("AWS:team", "web")}, wantKeys: []string{"AWS:team"}, wantContains: "reserved"},
+		{
+			name:     "every offending tag",
+			tags:     []*v1alpha1.Tag{tag("aws:a", ""), tag("ok", "fine"), tag("b*", "")},
+			wantKeys: []string{"aws:a", "b*"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.tags)
+			if len(tt.wantKeys) == 0 && !tt.wantTooMany {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate returned no error")
+			}
+			var keys []string
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var tagErr *TagError
+				if errors.As(e, &tagErr) {
+					keys = append(keys, tagErr.Key)
+				}
+			}
+			if fmt.Sprint(keys) != fmt.Sprint(tt.wantKeys) {
+				t.Errorf("Validate errors for keys %q, want %q", keys, tt.wantKeys)
+			}
+			if got := strings.Contains(err.Error(), "too many tags"); got != tt.wantTooMany {
+				t.Errorf("Validate error %q reports too many tags: %v, want %v", err, got, tt.wantTooMany)
+			}
+			if !strings.Contains(err.Error(), tt.wantContains) {
+				t.Errorf("Validate error %q, want it to contain %q", err, tt.wantContains)
+			}
+		})
+	}
+}
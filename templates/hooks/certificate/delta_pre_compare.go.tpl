@@ -1,2 +1,9 @@
 compareCertificateIssuedAt(delta, a, b)
-compareKeyAlgorithm(delta, a, b)
\ No newline at end of file
+compareKeyAlgorithm(delta, a, b)
+compareSubjectAlternativeNames(delta, a, b)
+compareReplacementStatus(delta, a, b)
+compareImportSourceSerial(delta, a, b)
+compareReplicas(delta, a, b)
+compareValidationStatus(delta, a, b)
+compareConsumers(delta, a, b)
+compareGatewayListeners(delta, a, b)
@@ -0,0 +1 @@
+	return rm.ensureTags(ctx, r, md)
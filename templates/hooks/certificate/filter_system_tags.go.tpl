@@ -0,0 +1 @@
+	rm.filterSystemTags(r, systemTags)
@@ -7,4 +7,13 @@
 		if observedKo.Spec.SubjectAlternativeNames != nil && latestKo.Spec.SubjectAlternativeNames == nil {
 			latestKo.Spec.SubjectAlternativeNames = observedKo.Spec.SubjectAlternativeNames
 		}
+		// Options are late initialized as a whole, so the export preference
+		// of Options that are set without one is filled in separately.
+		if observedKo.Spec.Options != nil && observedKo.Spec.Options.Export != nil &&
+			latestKo.Spec.Options != nil && latestKo.Spec.Options.Export == nil {
+			latestKo.Spec.Options.Export = observedKo.Spec.Options.Export
+		}
+	}
+	if requeueAfter := certificateRequeueAfter(rm.concreteResource(observed).ko, time.Now()); requeueAfter > 0 {
+		return rm.lateInitializeAndRequeue(observed, latestCopy, requeueAfter)
 	}
@@ -1,20 +1,3 @@
-// We only support DNS-based validation, because
-// certificate renewal is not really automatable when email verification
-// is used.
-//
-// See discussion here:
-// https://docs.aws.amazon.com/acm/latest/userguide/email-validation.html
-//
-// Unfortunately, because fields in the "ignore" configuration list are
-// now deleted from the aws-sdk-go private/model/api.Shape object,
-// setting `override_values` does not work.
-
-input.ValidationMethod = "DNS"
-
-// NOTE: exportPreference can ONLY be set for public certificates
-if desired.ko.Spec.ExportTo != nil && desired.ko.Spec.CertificateAuthorityARN == nil && desired.ko.Spec.CertificateAuthorityRef == nil {
-    if input.Options == nil {
-        input.Options = &svcsdktypes.CertificateOptions{}
-    }
-    input.Options.Export = "ENABLED"
-}
\ No newline at end of file
+	if err := rm.setRequestCertificateDefaults(ctx, desired, input); err != nil {
+		return nil, err
+	}
@@ -1,10 +1,22 @@
+    if isDryRun(ctx, desired) {
+        return rm.planCreate(ctx, desired)
+    }
+    if desired, err = rm.applyCertificateClass(ctx, desired); err != nil {
+        return nil, err
+    }
     created, isImport, err := rm.maybeImportCertificate(ctx, desired)
     if err != nil {
-        return nil, err
+        return created, err
     }
     if isImport {
         return created, nil
     }
-	if err = validatePublicValidationOptions(desired); err != nil {
-		return nil, ackerr.NewTerminalError(err)
+	if err = validateCertificateRequest(desired); err != nil {
+		return nil, err
+	}
+	if err = rm.checkCertificatePolicies(ctx, desired.ko.Namespace, requestedPolicyRequest(desired.ko)); err != nil {
+		return nil, err
+	}
+	if desired, err = rm.awaitApproval(ctx, desired, requestedPolicyRequest(desired.ko)); err != nil {
+		return desired, err
 	}
@@ -0,0 +1,15 @@
+	if isDryRun(ctx, r) {
+		return rm.planDelete(ctx, r)
+	}
+	if err = rm.releaseConsumers(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.releaseGatewayListeners(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.deleteReplacementCertificates(ctx, r); err != nil {
+		return nil, err
+	}
+	if err = rm.deleteReplicas(ctx, r); err != nil {
+		return nil, err
+	}
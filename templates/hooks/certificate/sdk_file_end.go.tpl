@@ -26,6 +26,9 @@ func (rm *resourceManager) new{{ $inputShapeName }}(
 {{ GoCodeSetSDKForStruct $CRD "" "input" $inputRef "" "r.ko.Spec" 1 }}
     {{range $fieldName := Each "PrivateKey" "Certificate" "CertificateChain"}}
     {
+        if err := rm.checkSecretReferenceGrant(ctx, r.ko, r.ko.Spec.{{$fieldName}}, SecretGrantAccessRead); err != nil {
+            return nil, err
+        }
         tmpSecret, err := rm.rr.SecretValueFromReference(ctx, r.ko.Spec.{{$fieldName}})
         if err != nil {
             return nil, ackrequeue.Needed(err)
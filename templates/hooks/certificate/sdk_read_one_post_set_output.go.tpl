@@ -0,0 +1,5 @@
+	rm.setImportSourceSerial(ctx, &resource{ko})
+	rm.readReplicas(ctx, ko)
+	rm.readGatewayListeners(ctx, ko)
+	ko.Status.DryRunPlan = nil
+	setValidationExpiresAt(ko)
@@ -35,10 +35,13 @@
 	} else {
 		ko.Status.DomainValidations = nil
 	}
-	ko.Spec.Tags, err = listTags(
-		ctx, rm.sdkapi, rm.metrics,
+	observedTags, err := listTags(
+		ctx, rm.tagsClient(), rm.metrics,
 		string(*r.ko.Status.ACKResourceMetadata.ARN),
 	)
 	if err != nil {
 		return nil, err
+	}
+	if ko.Spec.Tags, err = rm.specTags(ctx, r, observedTags); err != nil {
+		return nil, err
 	}
\ No newline at end of file
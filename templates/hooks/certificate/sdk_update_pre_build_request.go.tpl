@@ -1,3 +1,29 @@
+	if isDryRun(ctx, desired) {
+		return rm.planUpdate(ctx, desired, latest, delta)
+	}
+	if delta.DifferentAt("Spec.Status.AnnotatedConsumers") {
+		// Consumers and Gateway listeners are patched first and their
+		// errors returned last, so that one that does not exist yet does not
+		// hold up the other updates.
+		var consumersErr error
+		if desired, consumersErr = rm.syncConsumers(ctx, desired, latest); consumersErr != nil {
+			defer func() {
+				if err == nil {
+					err = consumersErr
+				}
+			}()
+		}
+	}
+	if delta.DifferentAt("Spec.Status.GatewayListeners") {
+		var gatewaysErr error
+		if desired, gatewaysErr = rm.syncGatewayListeners(ctx, desired, latest); gatewaysErr != nil {
+			defer func() {
+				if err == nil {
+					err = gatewaysErr
+				}
+			}()
+		}
+	}
     if delta.DifferentAt("Spec.Status.IssuedAt") {
         rlog.Info("Exporting certificate due to IssuedAt change")
         if err = rm.exportCertificate(ctx, &resource{latest.ko}); err != nil {
@@ -33,20 +59,46 @@
     }
 
     if delta.DifferentAt("Spec.Tags") {
+		desiredTags, latestTags, err := rm.awsTagSets(ctx, desired, latest)
+		if err != nil {
+			return nil, err
+		}
 		if err := syncTags(
-			ctx, rm.sdkapi, rm.metrics,
+			ctx, rm.tagsClient(), rm.metrics,
 			string(*desired.ko.Status.ACKResourceMetadata.ARN),
-			desired.ko.Spec.Tags, latest.ko.Spec.Tags,
+			desiredTags, latestTags,
 		); err != nil {
 			return nil, err
 		}
+		if err := rm.syncReplicaTags(ctx, latest, desiredTags, latestTags); err != nil {
+			return nil, err
+		}
 	}
-	if !delta.DifferentExcept("Spec.Tags") {
-        return desired, nil
+	if delta.DifferentAt("Spec.Status.RetiredCertificateARNs") {
+		if desired, err = rm.deleteRetiredCertificates(ctx, desired); err != nil {
+			return nil, err
+		}
+	}
+	if delta.DifferentAt("Spec.Status.ValidationRetries") {
+		return rm.retryValidation(ctx, desired, latest)
+	}
+	if delta.DifferentAt("Spec.Status.Replicas") {
+		if desired, err = rm.syncReplicas(ctx, desired, latest); err != nil {
+			return desired, err
+		}
+	}
+	if !delta.DifferentExcept("Spec.Tags", "Spec.Status.RetiredCertificateARNs", "Spec.Status.Replicas", "Spec.Status.AnnotatedConsumers", "Spec.Status.GatewayListeners") {
+        return desired, replicasPendingError(desired.ko)
     }
 	if latest.ko.Status.Type != nil && *latest.ko.Status.Type == string(svcapitypes.CertificateType_IMPORTED) {
+		if delta.DifferentAt("Spec.Status.ImportSourceSerial") {
+			return rm.reimportCertificate(ctx, desired, latest)
+		}
 		if delta.DifferentAt("Spec.Options") {
 			return nil, ackerr.NewTerminalError(errors.New("only tags can be updated for an imported certificate"))
 		}
 		return desired, nil
 	}
+	if requiresReplacement(delta) || delta.DifferentAt("Spec.Status.ReplacementCertificateARN") {
+		return rm.replaceCertificate(ctx, desired, latest, delta)
+	}
@@ -105,12 +105,19 @@ def wait_until_deleted(
             break
 
 
+def client_for(certificate_arn):
+    """Returns an ACM client for the region of the supplied Certificate ARN,
+    so that replicas in other regions can be looked up too.
+    """
+    return boto3.client('acm', region_name=certificate_arn.split(':')[3])
+
+
 def get(certificate_arn):
     """Returns a dict containing the Certificate record from the ACM API.
 
     If no such Certificate exists, returns None.
     """
-    c = boto3.client('acm')
+    c = client_for(certificate_arn)
     try:
         resp = c.describe_certificate(CertificateArn=certificate_arn)
         return resp['Certificate']
@@ -124,7 +131,7 @@ def get_tags(certificate_arn):
 
     If no such Certificate exists, returns None.
     """
-    c = boto3.client('acm')
+    c = client_for(certificate_arn)
     try:
         resp = c.list_tags_for_certificate(
             CertificateArn=certificate_arn,
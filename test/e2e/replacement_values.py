@@ -15,4 +15,7 @@ ACM-specific test variables.
 """
 
 REPLACEMENT_VALUES = {
+    # A region other than the one the controller runs in, for replicas.
+    # Certificates used by CloudFront have to be in us-east-1.
+    "REPLICA_REGION": "us-east-1",
 }
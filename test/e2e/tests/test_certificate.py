@@ -30,9 +30,9 @@ from acktest import tags
 
 RESOURCE_PLURAL = 'certificates'
 
-# NOTE(jaypipes): requeue_on_success_seconds = 60 for certificates, and in the
-# tests we check for Status.Status, which will only appear after a successful
-# Describe
+# NOTE(jaypipes): certificates pending validation are requeued every 60
+# seconds, and in the tests we check for Status.Status, which will only appear
+# after a successful Describe
 CREATE_WAIT_AFTER_SECONDS = 65
 FAILED_WAIT_AFTER_SECONDS = 60
 DELETE_WAIT_AFTER_SECONDS = 30
@@ -40,6 +40,10 @@ DELETE_WAIT_AFTER_SECONDS = 30
 # Time we wait for the certificate to get to ACK.ResourceSynced=True
 MAX_WAIT_FOR_SYNCED_MINUTES = 1
 
+# How often and how many times we check for replicas to show up in the status
+REPLICAS_WAIT_PERIOD_SECONDS = 15
+MAX_WAIT_FOR_REPLICAS_PERIODS = 8
+
 
 @pytest.fixture
 def certificate_public(request) -> Tuple[k8s.CustomResourceReference, Dict]:
@@ -125,6 +129,51 @@ def certificate_import() -> Tuple[k8s.CustomResourceReference, Dict]:
         pass
 
 
+@pytest.fixture
+def certificate_import_from_secret() -> Tuple[k8s.CustomResourceReference, Dict]:
+    certificate_name = random_suffix_name("certificate-import-from", 30)
+    body = client.V1Secret()
+    private_key, cert = create_x509_certificate('ACK', 'services.k8s.aws', 'acm.services.k8s.aws')
+    body.data = {
+        'tls.key': base64.b64encode(private_key).decode('utf-8'),
+        'tls.crt': base64.b64encode(cert).decode('utf-8')
+    }
+    body.metadata = {'name': certificate_name}
+    body.type = 'kubernetes.io/tls'
+    api_client = k8s_client()
+    client.CoreV1Api(api_client).create_namespaced_secret('default', api_client.sanitize_for_serialization(body))
+
+    replacements = REPLACEMENT_VALUES.copy()
+    replacements['CERTIFICATE_NAME'] = certificate_name
+
+    resource_data = load_resource(
+        'certificate_imported_from_secret',
+        additional_replacements=replacements,
+    )
+
+    ref = k8s.CustomResourceReference(
+        CRD_GROUP, CRD_VERSION, RESOURCE_PLURAL,
+        certificate_name, namespace='default',
+    )
+    k8s.create_custom_resource(ref, resource_data)
+    cr = k8s.wait_resource_consumed_by_controller(ref)
+
+    assert cr is not None
+    assert k8s.get_resource_exists(ref)
+
+    time.sleep(CREATE_WAIT_AFTER_SECONDS)
+
+    yield ref, cr
+
+    try:
+        _, deleted = k8s.delete_custom_resource(ref, 3, 10)
+        assert deleted
+        certificate.wait_until_deleted(cr['status']['ackResourceMetadata']['arn'])
+        k8s.delete_secret('default', certificate_name)
+    except:
+        pass
+
+
 @service_marker
 @pytest.mark.canary
 class TestCertificate:
@@ -164,7 +213,8 @@ class TestCertificate:
         time.sleep(FAILED_WAIT_AFTER_SECONDS)
 
         # The corresponding CR should be updated to a FAILED status as well
-        # because we have requeue_on_success_seconds = 60...
+        # because certificates pending validation are requeued every 60
+        # seconds...
         cr = k8s.get_resource(ref)
         assert "status" in cr
         assert 'status' in cr['status']
@@ -236,7 +286,7 @@ class TestCertificate:
         cond = k8s.get_resource_condition(ref, condition.CONDITION_TYPE_TERMINAL)
         assert cond is not None
         assert cond == {
-            'message': 'Too many domain validation errors',
+            'message': 'spec.domainValidationOptions: Too many: 7: must have at most 5 items',
             'status': 'True',
             'type': condition.CONDITION_TYPE_TERMINAL,
         }
@@ -279,6 +329,100 @@ class TestCertificate:
         time.sleep(DELETE_WAIT_AFTER_SECONDS)
         certificate.wait_until_deleted(certificate_arn)
 
+    @pytest.mark.parametrize('certificate_public', ['certificate_public'], indirect=True)
+    def test_immutable_field_change_rejected(
+            self,
+            certificate_public,
+    ):
+        """Test that changing a field ACM cannot update is rejected when no
+        replacement policy is set, and that the certificate is left untouched.
+        """
+        (ref, cr) = certificate_public
+        certificate_arn = cr["status"]["ackResourceMetadata"]["arn"]
+
+        assert k8s.wait_on_condition(
+            ref,
+            "ACK.ResourceSynced",
+            "True",
+            wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES,
+        )
+
+        updates = {
+            "spec": {
+                "subjectAlternativeNames": ["example.com", "www.example.com"],
+            },
+        }
+        k8s.patch_custom_resource(ref, updates)
+        time.sleep(10)
+
+        assert k8s.wait_on_condition(
+            ref,
+            condition.CONDITION_TYPE_TERMINAL,
+            "True",
+            wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES,
+        )
+        cr = k8s.get_resource(ref)
+        assert cr["status"]["ackResourceMetadata"]["arn"] == certificate_arn
+        assert "replacementCertificateARN" not in cr["status"]
+
+        k8s.delete_custom_resource(ref)
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        certificate.wait_until_deleted(certificate_arn)
+
+    @pytest.mark.parametrize('certificate_public', ['certificate_public_replicated'], indirect=True)
+    def test_replica_regions(
+            self,
+            certificate_public,
+    ):
+        """Test that the certificate is requested in each region of
+        replicaRegions, that the replicas are tracked in status.replicas, and
+        that removing a region deletes its replica.
+        """
+        (ref, cr) = certificate_public
+        certificate_arn = cr["status"]["ackResourceMetadata"]["arn"]
+        replica_region = REPLACEMENT_VALUES["REPLICA_REGION"]
+
+        # Replicas are requested on the first sync after the certificate
+        # itself was requested.
+        for _ in range(MAX_WAIT_FOR_REPLICAS_PERIODS):
+            cr = k8s.get_resource(ref)
+            if cr["status"].get("replicas"):
+                break
+            time.sleep(REPLICAS_WAIT_PERIOD_SECONDS)
+        replicas = cr["status"].get("replicas", [])
+        assert len(replicas) == 1
+        assert replicas[0]["region"] == replica_region
+        replica_arn = replicas[0]["certificateARN"]
+        assert replica_arn.split(":")[3] == replica_region
+        assert certificate.get(replica_arn) is not None
+
+        observed_tags = certificate.get_tags(replica_arn)
+        tags_dict = tags.to_dict(
+            [{"key": "environment", "value": "dev"}],
+            key_member_name="key",
+            value_member_name="value"
+        )
+        tags.assert_equal_without_ack_tags(
+            expected=tags_dict,
+            actual=observed_tags,
+        )
+
+        updates = {
+            "spec": {
+                "replicaRegions": None,
+            },
+        }
+        k8s.patch_custom_resource(ref, updates)
+        certificate.wait_until_deleted(replica_arn)
+
+        cr = k8s.get_resource(ref)
+        assert "replicas" not in cr["status"]
+        assert cr["status"]["ackResourceMetadata"]["arn"] == certificate_arn
+
+        k8s.delete_custom_resource(ref)
+        time.sleep(DELETE_WAIT_AFTER_SECONDS)
+        certificate.wait_until_deleted(certificate_arn)
+
     def test_import_certificate(
             self,
             certificate_import,
@@ -334,6 +478,26 @@ class TestCertificate:
         time.sleep(DELETE_WAIT_AFTER_SECONDS)
         certificate.wait_until_deleted(certificate_arn)
 
+    def test_import_certificate_from_tls_secret(
+            self,
+            certificate_import_from_secret,
+    ):
+        (ref, cr) = certificate_import_from_secret
+        assert k8s.wait_on_condition(
+            ref,
+            condition.CONDITION_TYPE_RESOURCE_SYNCED,
+            "True",
+            wait_periods=MAX_WAIT_FOR_SYNCED_MINUTES,
+        )
+        assert k8s.get_resource_condition(ref, condition.CONDITION_TYPE_TERMINAL) is None
+
+        cr = k8s.get_resource(ref)
+        status = cr['status']
+        certificate_arn = status['ackResourceMetadata']['arn']
+        assert status['type_'] == 'IMPORTED'
+        assert status['subject'] == 'O=ACK,CN=services.k8s.aws'
+        assert certificate.get(certificate_arn) is not None
+
 
 def k8s_client():
     return k8s._get_k8s_api_client()